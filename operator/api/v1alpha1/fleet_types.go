@@ -53,18 +53,35 @@ type FleetScaling struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=oldest_first;smallest_first
 	AgePriority Priority `json:"agePriority"`
+	// How many servers can be draining at once when scaling down, servers already terminating count toward it.
+	// There is no limit when it is not set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentDrains *int32 `json:"maxConcurrentDrains,omitempty"`
 }
 
 // FleetStatus defines the observed state of Fleet
 type FleetStatus struct {
 	Conditions      []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 	CurrentReplicas int32              `json:"current_replicas,omitempty"`
+	// How many servers of the fleet are ready or allocated
+	ReadyReplicas int32 `json:"ready_replicas,omitempty"`
+	// The sum of players over all servers of the fleet
+	Players int32 `json:"players,omitempty"`
+	// The sum of capacity over all servers of the fleet
+	Capacity int32 `json:"capacity,omitempty"`
+	// The label selector of the servers and pods of the fleet, used by the scale subresource
+	Selector string `json:"selector,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.scaling.replicas,statuspath=.status.current_replicas,selectorpath=.status.selector
 // +kubebuilder:printcolumn:name="Desired Replicas",type=integer,JSONPath=`.spec.scaling.replicas`
 // +kubebuilder:printcolumn:name="Current Replicas",type=integer,JSONPath=`.status.current_replicas`
+// +kubebuilder:printcolumn:name="Ready Replicas",type=integer,JSONPath=`.status.ready_replicas`
+// +kubebuilder:printcolumn:name="Players",type=integer,JSONPath=`.status.players`
+// +kubebuilder:printcolumn:name="Capacity",type=integer,JSONPath=`.status.capacity`
 
 // Fleet is the Schema for the fleets API
 type Fleet struct {
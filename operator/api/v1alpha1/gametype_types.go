@@ -18,11 +18,79 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+type UpdateStrategyType string
+
+const (
+	// RollingUpdateStrategy moves replicas from the old fleet to the new fleet step by step
+	RollingUpdateStrategy UpdateStrategyType = "RollingUpdate"
+	// RecreateStrategy deletes the old fleet, and creates the new fleet once all of its servers are gone
+	RecreateStrategy UpdateStrategyType = "Recreate"
+	// CanaryStrategy runs the new fleet next to the old fleet with a few replicas, until it is promoted or aborted
+	CanaryStrategy UpdateStrategyType = "Canary"
+)
+
+const (
+	// CanaryAnnotation is set on a GameType to promote or abort its running canary
+	CanaryAnnotation = "gameserver.falloria.com/canary"
+	// CanaryPromote replaces the old fleet with the canary fleet, using a rolling update
+	CanaryPromote = "promote"
+	// CanaryAbort deletes the canary fleet and reverts the server spec to the one of the old fleet
+	CanaryAbort = "abort"
+)
+
+// RollbackAnnotation is set on a GameType to the number of a revision in its status, to roll the server spec back to it
+const RollbackAnnotation = "gameserver.falloria.com/rollback-to"
+
 // GameTypeSpec defines the desired state of GameType
 type GameTypeSpec struct {
 	FleetSpec FleetSpec `json:"fleetSpec"`
+	// How the fleet is replaced when the server spec changes
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={type: RollingUpdate}
+	Strategy UpdateStrategy `json:"strategy,omitempty"`
+	// How many revisions of the server spec are kept, to roll back to
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+type UpdateStrategy struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=RollingUpdate
+	// +kubebuilder:validation:Enum=RollingUpdate;Recreate;Canary
+	Type UpdateStrategyType `json:"type,omitempty"`
+	// Used when the type is RollingUpdate, or when a canary is promoted
+	// +kubebuilder:validation:Optional
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+	// Only used when the type is Canary
+	// +kubebuilder:validation:Optional
+	Canary *CanaryUpdate `json:"canary,omitempty"`
+}
+
+type CanaryUpdate struct {
+	// How many replicas the canary fleet runs, as an absolute number or percentage of the replicas. It is at least 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10%"
+	// +kubebuilder:validation:XIntOrString
+	Replicas *intstr.IntOrString `json:"replicas,omitempty"`
+}
+
+type RollingUpdate struct {
+	// How many servers can exist above the desired replicas during the update, as an absolute number or percentage
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="25%"
+	// +kubebuilder:validation:XIntOrString
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// How many servers can be unavailable below the desired replicas during the update, as an absolute number or percentage
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="25%"
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // GameTypeStatus defines the observed state of GameType
@@ -31,10 +99,40 @@ type GameTypeStatus struct {
 	CurrentFleetName string             `json:"fleetName"`
 	// +kubebuilder:default=0
 	CurrentFleetReplicas int32 `json:"fleetReplicas"`
+	// The fleet running the new server spec as a canary, while it is not promoted
+	CanaryFleetName string `json:"canaryFleetName,omitempty"`
+	// The sum of players over all fleets of the gametype
+	Players int32 `json:"players,omitempty"`
+	// The sum of capacity over all fleets of the gametype
+	Capacity int32 `json:"capacity,omitempty"`
+	// The sum of servers over all fleets of the gametype
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+	// The label selector of the servers and pods of the gametype, used by the scale subresource
+	Selector string `json:"selector,omitempty"`
+	// The revision of the server spec the newest fleet was created with
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+	// The last server specs fleets were created with, oldest first
+	Revisions []GameTypeRevision `json:"revisions,omitempty"`
+}
+
+// GameTypeRevision is a server spec a fleet of the gametype was created with.
+// The server spec itself is stored in the ControllerRevision named after the gametype and the hash, to keep the status small.
+type GameTypeRevision struct {
+	Revision  int64       `json:"revision"`
+	FleetName string      `json:"fleetName"`
+	CreatedAt metav1.Time `json:"createdAt"`
+	// The hash of the server spec, which names its ControllerRevision
+	Hash string `json:"hash"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.fleetSpec.scaling.replicas,statuspath=.status.currentReplicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Fleet",type=string,JSONPath=`.status.fleetName`
+// +kubebuilder:printcolumn:name="Canary",type=string,JSONPath=`.status.canaryFleetName`
+// +kubebuilder:printcolumn:name="Players",type=integer,JSONPath=`.status.players`
+// +kubebuilder:printcolumn:name="Capacity",type=integer,JSONPath=`.status.capacity`
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.status.currentRevision`
 
 // GameType is the Schema for the gametypes API
 type GameType struct {
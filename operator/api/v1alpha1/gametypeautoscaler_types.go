@@ -17,41 +17,171 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// AutoscalerSecretLabel has to be set to "true" on every secret an autoscaler references.
+// Autoscalers send their secrets to an address chosen by whoever creates them, so the label makes the owner of a
+// secret opt in to it leaving the cluster, instead of any secret in the namespace being readable that way.
+const AutoscalerSecretLabel = "gameserver.falloria.com/autoscaler-secret"
+
 type PolicyStrategy string
 type SyncStrategy string
+type TargetKind string
 
 var validPolicyStrategies = map[PolicyStrategy]struct{}{
-	Webhook: {},
+	Webhook:    {},
+	Buffer:     {},
+	Schedule:   {},
+	Prometheus: {},
+	Grpc:       {},
 	// Add new strategies here as needed
 }
 var validSyncStrategy = map[SyncStrategy]struct{}{
 	FixedInterval: {},
+	OnChange:      {},
 	// Add new strategies here as needed
 }
+var validTargetKinds = map[TargetKind]struct{}{
+	GameTypeTarget: {},
+	FleetTarget:    {},
+}
 
 var (
-	Webhook PolicyStrategy = "webhook"
+	Webhook    PolicyStrategy = "webhook"
+	Buffer     PolicyStrategy = "buffer"
+	Schedule   PolicyStrategy = "schedule"
+	Prometheus PolicyStrategy = "prometheus"
+	Grpc       PolicyStrategy = "grpc"
 
 	FixedInterval SyncStrategy = "fixedinterval"
+	OnChange      SyncStrategy = "onchange"
+
+	GameTypeTarget TargetKind = "GameType"
+	FleetTarget    TargetKind = "Fleet"
 )
 
 //The following structs handle the policy of how to sync
 
 type AutoscalePolicy struct {
-	// +kubebuilder:validation:Enum=webhook
-	Type                  PolicyStrategy        `json:"type"`
-	WebhookAutoscalerSpec WebhookAutoscalerSpec `json:"webhook"`
+	// +kubebuilder:validation:Enum=webhook;buffer;schedule;prometheus;grpc
+	Type PolicyStrategy `json:"type"`
+	// Only used when the type is webhook
+	// +kubebuilder:validation:Optional
+	WebhookAutoscalerSpec *WebhookAutoscalerSpec `json:"webhook,omitempty"`
+	// Only used when the type is buffer
+	// +kubebuilder:validation:Optional
+	BufferAutoscalerSpec *BufferAutoscalerSpec `json:"buffer,omitempty"`
+	// Only used when the type is schedule
+	// +kubebuilder:validation:Optional
+	ScheduleAutoscalerSpec *ScheduleAutoscalerSpec `json:"schedule,omitempty"`
+	// Only used when the type is prometheus
+	// +kubebuilder:validation:Optional
+	PrometheusAutoscalerSpec *PrometheusAutoscalerSpec `json:"prometheus,omitempty"`
+	// Only used when the type is grpc
+	// +kubebuilder:validation:Optional
+	GrpcAutoscalerSpec *GrpcAutoscalerSpec `json:"grpc,omitempty"`
+}
+
+// BufferAutoscalerSpec keeps a buffer of free servers on top of the servers that are allocated or have players
+type BufferAutoscalerSpec struct {
+	// How many free servers to keep, as an absolute number or a percentage of the replicas
+	// +kubebuilder:validation:XIntOrString
+	BufferSize intstr.IntOrString `json:"bufferSize"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// GrpcAutoscalerSpec calls an external autoscaler implementing the Autoscaler service of api/proto/autoscaler/v1
+// The secret is read from the namespace of the autoscaler, and needs the AutoscalerSecretLabel
+type GrpcAutoscalerSpec struct {
+	// The host:port of the grpc server, for example scaler.games.svc.cluster.local:9000
+	Address string `json:"address"`
+	// PEM encoded CA bundle used to verify the certificate of the server
+	// When neither it nor the client certificate is set, the connection is not encrypted
+	// +kubebuilder:validation:Optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// Secret with the tls.crt and tls.key of the client certificate presented to the server
+	// +kubebuilder:validation:Optional
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
 }
 
+// PrometheusAutoscalerSpec sets the replicas from the value of a prometheus query
+// Like the HorizontalPodAutoscaler, the replicas are the value divided by the target value per replica, rounded up
+type PrometheusAutoscalerSpec struct {
+	// The base url of the prometheus server, for example http://prometheus.monitoring.svc:9090
+	ServerUrl string `json:"serverUrl"`
+	// The PromQL query, it has to return a single sample, like sum(players{game="lobby"})
+	Query string `json:"query"`
+	// The value of the query a single replica should handle
+	TargetValuePerReplica resource.Quantity `json:"targetValuePerReplica"`
+}
+
+// ScheduleAutoscalerSpec sets the replicas based on cron schedules.
+// When several entries are active, the one that started most recently wins, and on a tie the one listed first.
+type ScheduleAutoscalerSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Entries []ScheduleEntry `json:"entries"`
+	// The replicas used while no entry is active, the replicas are left alone when it is not set
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	DefaultReplicas *int32 `json:"defaultReplicas,omitempty"`
+}
+
+type ScheduleEntry struct {
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// When the entry starts, as a standard cron expression with 5 fields
+	Cron string `json:"cron"`
+	// The IANA time zone of the cron expression, defaults to UTC
+	// +kubebuilder:validation:Optional
+	TimeZone *string `json:"timeZone,omitempty"`
+	// How long the entry stays active after it starts, without it the entry stays active until another entry starts
+	// +kubebuilder:validation:Optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+}
+
+// WebhookAutoscalerSpec defines where the webhook is and how to call it
+// All secrets are read from the namespace of the autoscaler, and need the AutoscalerSecretLabel
 type WebhookAutoscalerSpec struct {
 	// +kubebuilder:validation:Optional
 	Url  *string `json:"url,omitempty"`
 	Path *string `json:"path"`
 	// +kubebuilder:validation:Optional
 	Service *Service `json:"service"`
+	// PEM encoded CA bundle used to verify the certificate of the webhook
+	// When it is set, the webhook of a service is called over https
+	// +kubebuilder:validation:Optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// Secret with the tls.crt and tls.key of the client certificate presented to the webhook
+	// When it is set, the webhook of a service is called over https
+	// +kubebuilder:validation:Optional
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+	// Key of a secret with the bearer token sent in the Authorization header
+	// +kubebuilder:validation:Optional
+	BearerTokenSecretRef *corev1.SecretKeySelector `json:"bearerTokenSecretRef,omitempty"`
+	// Extra headers sent to the webhook, with their values taken from secrets
+	// +kubebuilder:validation:Optional
+	Headers []WebhookHeader `json:"headers,omitempty"`
+	// Key of a secret used to sign the request body with HMAC-SHA256
+	// The signature is sent in the X-Fallernetes-Signature header as sha256=<hex>
+	// +kubebuilder:validation:Optional
+	HMACSecretRef *corev1.SecretKeySelector `json:"hmacSecretRef,omitempty"`
+}
+
+// WebhookHeader is a header sent to the webhook, with the value taken from a secret
+type WebhookHeader struct {
+	Name      string                   `json:"name"`
+	ValueFrom corev1.SecretKeySelector `json:"valueFrom"`
 }
 
 type Service struct {
@@ -62,26 +192,140 @@ type Service struct {
 
 // The following sync structs handle when to sync
 type Sync struct {
-	// +kubebuilder:validation:Enum=fixedinterval
-	Type SyncStrategy     `json:"type"`
-	Time *metav1.Duration `json:"interval"`
+	// +kubebuilder:validation:Enum=fixedinterval;onchange
+	Type SyncStrategy `json:"type"`
+	// How often to sync, it is required for fixedinterval
+	// For onchange it is the longest time between syncs when nothing changes, without it only changes trigger a sync
+	// +kubebuilder:validation:Optional
+	Time *metav1.Duration `json:"interval,omitempty"`
+	// Only used when the type is onchange, how long to wait for more changes before syncing
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="2s"
+	Debounce *metav1.Duration `json:"debounce,omitempty"`
+	// Only used when the type is onchange, the shortest time between two syncs
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10s"
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+}
+
+// AutoscalerBehavior configures how quickly the autoscaler follows its policy, like the behavior of the HorizontalPodAutoscaler
+// Without it, every recommendation is applied right away
+type AutoscalerBehavior struct {
+	// +kubebuilder:validation:Optional
+	ScaleUp *ScalingRules `json:"scaleUp,omitempty"`
+	// +kubebuilder:validation:Optional
+	ScaleDown *ScalingRules `json:"scaleDown,omitempty"`
+}
+
+// ScalingRules configures scaling in a single direction
+type ScalingRules struct {
+	// How far back the recommendations of the policy are considered
+	// Scaling up uses the lowest recommendation of the window and scaling down the highest one,
+	// so the replicas only change as far as the policy recommended for the whole window
+	// +kubebuilder:validation:Optional
+	StabilizationWindow *metav1.Duration `json:"stabilizationWindow,omitempty"`
+	// How long to wait after the autoscaler scaled the target, in either direction, before scaling in this direction
+	// +kubebuilder:validation:Optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// TargetRef points to the resource in the namespace of the autoscaler that is scaled
+type TargetRef struct {
+	// +kubebuilder:validation:Enum=GameType;Fleet
+	Kind TargetKind `json:"kind"`
+	Name string     `json:"name"`
+}
+
+// IsValidTargetKind returns true if the autoscaler can scale resources of the kind
+func IsValidTargetKind(kind TargetKind) bool {
+	_, ok := validTargetKinds[kind]
+	return ok
 }
 
 // GameTypeAutoscalerSpec defines the desired state of GameTypeAutoscaler.
 type GameTypeAutoscalerSpec struct {
-	GameTypeName    string          `json:"gameTypeName"`
+	// The gametype to scale, it is replaced by the targetRef and only used when the targetRef is not set
+	// +kubebuilder:validation:Optional
+	GameTypeName string `json:"gameTypeName,omitempty"`
+	// The gametype or fleet to scale
+	// Fleets that belong to a gametype are scaled by the gametype, so they are rejected and the gametype should be targeted instead
+	// +kubebuilder:validation:Optional
+	TargetRef       *TargetRef      `json:"targetRef,omitempty"`
 	AutoscalePolicy AutoscalePolicy `json:"policy"`
-	Sync            Sync            `json:"sync"`
+	// When to sync, it is required for every policy except schedule, which syncs on its own boundaries
+	// +kubebuilder:validation:Optional
+	Sync *Sync `json:"sync,omitempty"`
+	// The lowest replicas any policy can scale to
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// The highest replicas any policy can scale to
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// How many replicas can be added in a single sync, as an absolute number or a percentage of the current replicas
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	MaxScaleUpStep *intstr.IntOrString `json:"maxScaleUpStep,omitempty"`
+	// How many replicas can be removed in a single sync, as an absolute number or a percentage of the current replicas
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	MaxScaleDownStep *intstr.IntOrString `json:"maxScaleDownStep,omitempty"`
+	// The stabilization windows and cooldowns of scaling up and down
+	// +kubebuilder:validation:Optional
+	Behavior *AutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// GetTarget returns the resource the autoscaler scales, falling back to the gameTypeName without a targetRef
+func (s *GameTypeAutoscalerSpec) GetTarget() TargetRef {
+	if s.TargetRef != nil {
+		return *s.TargetRef
+	}
+	return TargetRef{Kind: GameTypeTarget, Name: s.GameTypeName}
+}
+
+const (
+	// AutoscalerReady is true while the autoscaler is configured correctly and its gametype exists
+	AutoscalerReady = "Ready"
+	// AutoscalerDegraded is true while the syncs of the autoscaler keep failing
+	AutoscalerDegraded = "Degraded"
+)
+
+// Recommendation is a recommendation of the policy, with the last time it was recommended
+type Recommendation struct {
+	Replicas int32       `json:"replicas"`
+	Time     metav1.Time `json:"time"`
 }
 
 // GameTypeAutoscalerStatus defines the observed state of GameTypeAutoscaler.
 type GameTypeAutoscalerStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// When the autoscaler last synced, whether it succeeded or not
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// The replicas the policy recommended on the last sync that asked for scaling, before the limits are applied
+	LastRecommendation *int32 `json:"lastRecommendation,omitempty"`
+	// The replicas the gametype was last scaled to by the autoscaler
+	LastAppliedReplicas *int32 `json:"lastAppliedReplicas,omitempty"`
+	// When the autoscaler last changed the replicas of the target, the cooldowns start from it
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+	// The recommendations within the longest stabilization window, kept so the windows survive a restart of the operator
+	Recommendations []Recommendation `json:"recommendations,omitempty"`
+	// How many syncs failed in a row, it is reset by a successful sync
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+	// The error of the last failed sync, like a failing webhook, it is cleared by a successful sync
+	LastError string `json:"lastError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="GameType",type=string,JSONPath=`.spec.gameTypeName`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetRef.name`
+// +kubebuilder:printcolumn:name="Policy",type=string,JSONPath=`.spec.policy.type`
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.lastAppliedReplicas`
+// +kubebuilder:printcolumn:name="Recommendation",type=integer,JSONPath=`.status.lastRecommendation`
+// +kubebuilder:printcolumn:name="Failures",type=integer,JSONPath=`.status.consecutiveFailures`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
 
 // GameTypeAutoscaler is the Schema for the gametypeautoscalers API.
 type GameTypeAutoscaler struct {
@@ -33,8 +33,27 @@ type ServerSpec struct {
 	SidecarSettings *SidecarSettings `json:"sidecar,omitempty"`
 	// +kubebuilder:validation:Optional
 	GameInfo *GameInfo `json:"gameInfo,omitempty"`
+	// What happens when a container of the server crashes, or the game misses its heartbeat deadline
+	// Replace and Fail set the restart policy of the pod to Never, so the crash is not hidden by the kubelet
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Restart
+	// +kubebuilder:validation:Enum=Restart;Replace;Fail
+	RestartPolicy ServerRestartPolicy `json:"restartPolicy,omitempty"`
 }
 
+// ServerRestartPolicy describes how a server recovers from a crashed container
+type ServerRestartPolicy string
+
+const (
+	// ServerRestartInPlace lets the kubelet restart the crashed container in the same pod, using the restart policy of the pod
+	// A game that misses its heartbeat deadline is marked as unhealthy, and restarted by the kubelet through a liveness probe
+	ServerRestartInPlace ServerRestartPolicy = "Restart"
+	// ServerRestartReplace deletes the pod of the crashed or unhealthy server, and the server gets a new pod after a backoff
+	ServerRestartReplace ServerRestartPolicy = "Replace"
+	// ServerRestartFail marks the crashed or unhealthy server as failed, and its fleet replaces it with a new server
+	ServerRestartFail ServerRestartPolicy = "Fail"
+)
+
 type GameInfo struct {
 	// +kubebuilder:validation:Optional
 	Capacity *int `json:"capacity,omitempty"`
@@ -49,15 +68,110 @@ type SidecarSettings struct {
 	SidecarImage *string `json:"image,omitempty"`
 	// +kubebuilder:validation:Optional
 	LogDebug bool `json:"logDebug,omitempty"`
+	// How the operator learns the state of the sidecar
+	// Poll asks the sidecar over http, Push lets the sidecar write its state to the annotations of its pod
+	// In Push mode, a pod without a service account gets one that may only patch its own pod, and only the sidecar gets its token
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Poll
+	// +kubebuilder:validation:Enum=Poll;Push
+	Mode SidecarMode `json:"mode,omitempty"`
+	// How long the game may go without calling the heartbeat endpoint of the sidecar, before the server is unhealthy
+	// The first deadline starts when the sidecar starts. Heartbeats are not required when this is not set
+	// With the Restart policy, game containers without a liveness probe get one on the sidecar, so a hung game is restarted in place
+	// +kubebuilder:validation:Optional
+	HeartbeatDeadline *metav1.Duration `json:"heartbeatDeadline,omitempty"`
 }
 
+// SidecarMode describes how the operator learns the state of the sidecar
+type SidecarMode string
+
+const (
+	// SidecarModePoll makes the operator ask the sidecar for its state over http
+	SidecarModePoll SidecarMode = "Poll"
+	// SidecarModePush makes the sidecar patch its state into the annotations of its pod, which the operator reads from its cache
+	SidecarModePush SidecarMode = "Push"
+)
+
+// The annotations the sidecar writes to its pod in the Push mode
+const (
+	DeleteAllowedAnnotation        = "gameserver.falloria.com/delete-allowed"
+	ShutdownAcknowledgedAnnotation = "gameserver.falloria.com/shutdown-acknowledged"
+	PlayersAnnotation              = "gameserver.falloria.com/players"
+	// HeartbeatHealthyAnnotation is false while the game missed its heartbeat deadline
+	HeartbeatHealthyAnnotation = "gameserver.falloria.com/heartbeat-healthy"
+)
+
+// ServerState describes in which part of its lifecycle a Server currently is
+type ServerState string
+
+const (
+	// ServerStateCreating is set once the pod for the server has been created
+	ServerStateCreating ServerState = "Creating"
+	// ServerStateStarting is set while the pod is scheduled, but not all containers are ready
+	ServerStateStarting ServerState = "Starting"
+	// ServerStateReady is set when the pod is running and all containers are ready
+	ServerStateReady ServerState = "Ready"
+	// ServerStateUnhealthy is set when the game of a ready server missed its heartbeat deadline, with the Restart restart policy
+	// The server becomes ready again once the game sends heartbeats
+	ServerStateUnhealthy ServerState = "Unhealthy"
+	// ServerStateAllocated is set when a ready server was claimed by a ServerAllocation, it is never picked for scale-down
+	// The server is released when the allocation is deleted, or when its game allows the deletion
+	ServerStateAllocated ServerState = "Allocated"
+	// ServerStateShutdownRequested is set when the server was marked for deletion, but the sidecar has not been told yet
+	ServerStateShutdownRequested ServerState = "ShutdownRequested"
+	// ServerStateDraining is set when the sidecar received the shutdown request, but the game has not allowed deletion
+	ServerStateDraining ServerState = "Draining"
+	// ServerStateDeleteAllowed is set when the server can be deleted, either by the game allowing it, force or timeout
+	ServerStateDeleteAllowed ServerState = "DeleteAllowed"
+	// ServerStateTerminating is set when the pod has been deleted and the server is being finalized
+	ServerStateTerminating ServerState = "Terminating"
+	// ServerStateFailed is set when the pod of the server has failed, or it crashed with the Fail restart policy
+	ServerStateFailed ServerState = "Failed"
+)
+
 // ServerStatus defines the observed state of Server
 type ServerStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// The current lifecycle state of the server
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Creating;Starting;Ready;Unhealthy;Allocated;ShutdownRequested;Draining;DeleteAllowed;Terminating;Failed
+	State ServerState `json:"state,omitempty"`
+	// The amount of players the game reported to the sidecar
+	// +kubebuilder:validation:Optional
+	Players int32 `json:"players,omitempty"`
+	// Whether the game allowed the deletion of the server, an allocated server is released once it does
+	// +kubebuilder:validation:Optional
+	DeleteAllowed bool `json:"deleteAllowed,omitempty"`
+	// The ServerAllocation that claimed the server, while it is allocated
+	// +kubebuilder:validation:Optional
+	Allocation string `json:"allocation,omitempty"`
+	// How often the kubelet restarted the containers of the current pod in place
+	// +kubebuilder:validation:Optional
+	Restarts int32 `json:"restarts,omitempty"`
+	// How often the pod was replaced after a crash, with the Replace restart policy
+	// +kubebuilder:validation:Optional
+	Replacements int32 `json:"replacements,omitempty"`
+	// When the pod was last replaced, the new pod is only created after a backoff that grows with the replacements
+	// +kubebuilder:validation:Optional
+	LastReplacementTime *metav1.Time `json:"lastReplacementTime,omitempty"`
+}
+
+// IsDeleting returns true if the server is in one of the states that happen after deletion was requested
+func (s ServerState) IsDeleting() bool {
+	switch s {
+	case ServerStateShutdownRequested, ServerStateDraining, ServerStateDeleteAllowed, ServerStateTerminating:
+		return true
+	}
+	return false
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Players",type=integer,JSONPath=`.status.players`
+// +kubebuilder:printcolumn:name="Capacity",type=integer,JSONPath=`.spec.gameInfo.capacity`
+// +kubebuilder:printcolumn:name="Restarts",type=integer,JSONPath=`.status.restarts`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Server is the Schema for the servers API
 type Server struct {
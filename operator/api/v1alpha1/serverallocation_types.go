@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AllocationState describes the outcome of a ServerAllocation
+type AllocationState string
+
+const (
+	// AllocationStateAllocated is set when a ready server was claimed for the allocation
+	AllocationStateAllocated AllocationState = "Allocated"
+	// AllocationStateUnAllocated is set when no ready server matched the allocation
+	AllocationStateUnAllocated AllocationState = "UnAllocated"
+	// AllocationStateInvalid is set when the spec of the allocation cannot be used
+	AllocationStateInvalid AllocationState = "Invalid"
+)
+
+// ServerAllocationSpec defines the desired state of ServerAllocation.
+// Exactly one of FleetName and GameTypeName has to be set.
+type ServerAllocationSpec struct {
+	// The fleet to allocate a server from
+	// +kubebuilder:validation:Optional
+	FleetName string `json:"fleetName,omitempty"`
+	// The gametype to allocate a server from, any of its fleets can be used
+	// +kubebuilder:validation:Optional
+	GameTypeName string `json:"gameTypeName,omitempty"`
+	// Additional labels the allocated server has to match
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ServerAllocationStatus defines the observed state of ServerAllocation.
+type ServerAllocationStatus struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Allocated;UnAllocated;Invalid
+	State AllocationState `json:"state,omitempty"`
+	// +kubebuilder:validation:Optional
+	ServerName string `json:"serverName,omitempty"`
+	// +kubebuilder:validation:Optional
+	PodIP string `json:"podIP,omitempty"`
+	// +kubebuilder:validation:Optional
+	NodeName string `json:"nodeName,omitempty"`
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Server",type=string,JSONPath=`.status.serverName`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ServerAllocation is the Schema for the serverallocations API.
+type ServerAllocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServerAllocationSpec   `json:"spec,omitempty"`
+	Status ServerAllocationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServerAllocationList contains a list of ServerAllocation.
+type ServerAllocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServerAllocation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServerAllocation{}, &ServerAllocationList{})
+}
@@ -21,14 +21,40 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoscalePolicy) DeepCopyInto(out *AutoscalePolicy) {
 	*out = *in
-	in.WebhookAutoscalerSpec.DeepCopyInto(&out.WebhookAutoscalerSpec)
+	if in.WebhookAutoscalerSpec != nil {
+		in, out := &in.WebhookAutoscalerSpec, &out.WebhookAutoscalerSpec
+		*out = new(WebhookAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BufferAutoscalerSpec != nil {
+		in, out := &in.BufferAutoscalerSpec, &out.BufferAutoscalerSpec
+		*out = new(BufferAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduleAutoscalerSpec != nil {
+		in, out := &in.ScheduleAutoscalerSpec, &out.ScheduleAutoscalerSpec
+		*out = new(ScheduleAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusAutoscalerSpec != nil {
+		in, out := &in.PrometheusAutoscalerSpec, &out.PrometheusAutoscalerSpec
+		*out = new(PrometheusAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrpcAutoscalerSpec != nil {
+		in, out := &in.GrpcAutoscalerSpec, &out.GrpcAutoscalerSpec
+		*out = new(GrpcAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalePolicy.
@@ -41,6 +67,77 @@ func (in *AutoscalePolicy) DeepCopy() *AutoscalePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalerBehavior) DeepCopyInto(out *AutoscalerBehavior) {
+	*out = *in
+	if in.ScaleUp != nil {
+		in, out := &in.ScaleUp, &out.ScaleUp
+		*out = new(ScalingRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleDown != nil {
+		in, out := &in.ScaleDown, &out.ScaleDown
+		*out = new(ScalingRules)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerBehavior.
+func (in *AutoscalerBehavior) DeepCopy() *AutoscalerBehavior {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalerBehavior)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BufferAutoscalerSpec) DeepCopyInto(out *BufferAutoscalerSpec) {
+	*out = *in
+	out.BufferSize = in.BufferSize
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BufferAutoscalerSpec.
+func (in *BufferAutoscalerSpec) DeepCopy() *BufferAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BufferAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpdate) DeepCopyInto(out *CanaryUpdate) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpdate.
+func (in *CanaryUpdate) DeepCopy() *CanaryUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Fleet) DeepCopyInto(out *Fleet) {
 	*out = *in
@@ -103,6 +200,11 @@ func (in *FleetList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FleetScaling) DeepCopyInto(out *FleetScaling) {
 	*out = *in
+	if in.MaxConcurrentDrains != nil {
+		in, out := &in.MaxConcurrentDrains, &out.MaxConcurrentDrains
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetScaling.
@@ -119,7 +221,7 @@ func (in *FleetScaling) DeepCopy() *FleetScaling {
 func (in *FleetSpec) DeepCopyInto(out *FleetSpec) {
 	*out = *in
 	in.ServerSpec.DeepCopyInto(&out.ServerSpec)
-	out.Scaling = in.Scaling
+	in.Scaling.DeepCopyInto(&out.Scaling)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetSpec.
@@ -154,6 +256,26 @@ func (in *FleetStatus) DeepCopy() *FleetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GameInfo) DeepCopyInto(out *GameInfo) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GameInfo.
+func (in *GameInfo) DeepCopy() *GameInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(GameInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GameType) DeepCopyInto(out *GameType) {
 	*out = *in
@@ -187,7 +309,7 @@ func (in *GameTypeAutoscaler) DeepCopyInto(out *GameTypeAutoscaler) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GameTypeAutoscaler.
@@ -243,8 +365,42 @@ func (in *GameTypeAutoscalerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GameTypeAutoscalerSpec) DeepCopyInto(out *GameTypeAutoscalerSpec) {
 	*out = *in
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(TargetRef)
+		**out = **in
+	}
 	in.AutoscalePolicy.DeepCopyInto(&out.AutoscalePolicy)
-	in.Sync.DeepCopyInto(&out.Sync)
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(Sync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxScaleUpStep != nil {
+		in, out := &in.MaxScaleUpStep, &out.MaxScaleUpStep
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxScaleDownStep != nil {
+		in, out := &in.MaxScaleDownStep, &out.MaxScaleDownStep
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(AutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GameTypeAutoscalerSpec.
@@ -260,6 +416,38 @@ func (in *GameTypeAutoscalerSpec) DeepCopy() *GameTypeAutoscalerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GameTypeAutoscalerStatus) DeepCopyInto(out *GameTypeAutoscalerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRecommendation != nil {
+		in, out := &in.LastRecommendation, &out.LastRecommendation
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastAppliedReplicas != nil {
+		in, out := &in.LastAppliedReplicas, &out.LastAppliedReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = make([]Recommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GameTypeAutoscalerStatus.
@@ -304,10 +492,32 @@ func (in *GameTypeList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GameTypeRevision) DeepCopyInto(out *GameTypeRevision) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GameTypeRevision.
+func (in *GameTypeRevision) DeepCopy() *GameTypeRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(GameTypeRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GameTypeSpec) DeepCopyInto(out *GameTypeSpec) {
 	*out = *in
 	in.FleetSpec.DeepCopyInto(&out.FleetSpec)
+	in.Strategy.DeepCopyInto(&out.Strategy)
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GameTypeSpec.
@@ -330,6 +540,13 @@ func (in *GameTypeStatus) DeepCopyInto(out *GameTypeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]GameTypeRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GameTypeStatus.
@@ -342,6 +559,165 @@ func (in *GameTypeStatus) DeepCopy() *GameTypeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcAutoscalerSpec) DeepCopyInto(out *GrpcAutoscalerSpec) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcAutoscalerSpec.
+func (in *GrpcAutoscalerSpec) DeepCopy() *GrpcAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusAutoscalerSpec) DeepCopyInto(out *PrometheusAutoscalerSpec) {
+	*out = *in
+	out.TargetValuePerReplica = in.TargetValuePerReplica.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusAutoscalerSpec.
+func (in *PrometheusAutoscalerSpec) DeepCopy() *PrometheusAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Recommendation) DeepCopyInto(out *Recommendation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Recommendation.
+func (in *Recommendation) DeepCopy() *Recommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(Recommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdate) DeepCopyInto(out *RollingUpdate) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdate.
+func (in *RollingUpdate) DeepCopy() *RollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingRules) DeepCopyInto(out *ScalingRules) {
+	*out = *in
+	if in.StabilizationWindow != nil {
+		in, out := &in.StabilizationWindow, &out.StabilizationWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingRules.
+func (in *ScalingRules) DeepCopy() *ScalingRules {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleAutoscalerSpec) DeepCopyInto(out *ScheduleAutoscalerSpec) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]ScheduleEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultReplicas != nil {
+		in, out := &in.DefaultReplicas, &out.DefaultReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleAutoscalerSpec.
+func (in *ScheduleAutoscalerSpec) DeepCopy() *ScheduleAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleEntry) DeepCopyInto(out *ScheduleEntry) {
+	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleEntry.
+func (in *ScheduleEntry) DeepCopy() *ScheduleEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Server) DeepCopyInto(out *Server) {
 	*out = *in
@@ -369,6 +745,100 @@ func (in *Server) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerAllocation) DeepCopyInto(out *ServerAllocation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerAllocation.
+func (in *ServerAllocation) DeepCopy() *ServerAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServerAllocation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerAllocationList) DeepCopyInto(out *ServerAllocationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServerAllocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerAllocationList.
+func (in *ServerAllocationList) DeepCopy() *ServerAllocationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerAllocationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServerAllocationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerAllocationSpec) DeepCopyInto(out *ServerAllocationSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerAllocationSpec.
+func (in *ServerAllocationSpec) DeepCopy() *ServerAllocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerAllocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerAllocationStatus) DeepCopyInto(out *ServerAllocationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerAllocationStatus.
+func (in *ServerAllocationStatus) DeepCopy() *ServerAllocationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerAllocationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerList) DeepCopyInto(out *ServerList) {
 	*out = *in
@@ -415,6 +885,11 @@ func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
 		*out = new(SidecarSettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GameInfo != nil {
+		in, out := &in.GameInfo, &out.GameInfo
+		*out = new(GameInfo)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSpec.
@@ -437,6 +912,10 @@ func (in *ServerStatus) DeepCopyInto(out *ServerStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastReplacementTime != nil {
+		in, out := &in.LastReplacementTime, &out.LastReplacementTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerStatus.
@@ -477,6 +956,11 @@ func (in *SidecarSettings) DeepCopyInto(out *SidecarSettings) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.HeartbeatDeadline != nil {
+		in, out := &in.HeartbeatDeadline, &out.HeartbeatDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSettings.
@@ -497,6 +981,16 @@ func (in *Sync) DeepCopyInto(out *Sync) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Debounce != nil {
+		in, out := &in.Debounce, &out.Debounce
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sync.
@@ -509,6 +1003,46 @@ func (in *Sync) DeepCopy() *Sync {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetRef) DeepCopyInto(out *TargetRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetRef.
+func (in *TargetRef) DeepCopy() *TargetRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
+	*out = *in
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
+func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookAutoscalerSpec) DeepCopyInto(out *WebhookAutoscalerSpec) {
 	*out = *in
@@ -527,6 +1061,33 @@ func (in *WebhookAutoscalerSpec) DeepCopyInto(out *WebhookAutoscalerSpec) {
 		*out = new(Service)
 		**out = **in
 	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.BearerTokenSecretRef != nil {
+		in, out := &in.BearerTokenSecretRef, &out.BearerTokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]WebhookHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HMACSecretRef != nil {
+		in, out := &in.HMACSecretRef, &out.HMACSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookAutoscalerSpec.
@@ -538,3 +1099,19 @@ func (in *WebhookAutoscalerSpec) DeepCopy() *WebhookAutoscalerSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookHeader) DeepCopyInto(out *WebhookHeader) {
+	*out = *in
+	in.ValueFrom.DeepCopyInto(&out.ValueFrom)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookHeader.
+func (in *WebhookHeader) DeepCopy() *WebhookHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookHeader)
+	in.DeepCopyInto(out)
+	return out
+}
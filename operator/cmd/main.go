@@ -212,6 +212,8 @@ func main() {
 		Scheme:            mgr.GetScheme(),
 		Recorder:          mgr.GetEventRecorderFor("server-controller"),
 		DeletionAllowed:   prodChecker,
+		PlayerCounter:     utils.ProdPlayerCounter{},
+		HeartbeatChecker:  utils.ProdHeartbeatChecker{},
 		ErrorOnNotAllowed: false,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Server")
@@ -235,12 +237,24 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controller.GameTypeAutoscalerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Webhook:    utils.ProductionWebhookRequest{Client: mgr.GetClient(), SecretReader: mgr.GetAPIReader()},
+		Grpc:       utils.ProductionGrpcRequest{Client: mgr.GetClient(), SecretReader: mgr.GetAPIReader()},
+		Prometheus: utils.ProductionPrometheusQuery{},
+		Recorder:   mgr.GetEventRecorderFor("gametypeautoscaler"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GameTypeAutoscaler")
 		os.Exit(1)
 	}
+	if err = (&controller.ServerAllocationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("serverallocation"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ServerAllocation")
+		os.Exit(1)
+	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = webhookgameserverv1alpha1.SetupServerWebhookWithManager(mgr); err != nil {
@@ -21,9 +21,12 @@ import (
 	"fmt"
 	"github.com/MirrorStudios/fallernetes/internal/utils"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -80,17 +83,23 @@ func (r *FleetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	if err != nil {
 		return ctrl.Result{Requeue: true}, err
 	}
+	if err := r.replaceFailedServers(ctx, fleet, servers); err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
 	fleet.Status.CurrentReplicas = int32(len(servers.Items))
 	if fleet.Spec.Scaling.Replicas != fleet.Status.CurrentReplicas {
 		if err := r.scaleServerCount(ctx, fleet, req.Namespace); err != nil {
 			return ctrl.Result{}, err
 		}
-		servers, err := r.getServers(ctx, fleet)
+		servers, err = r.getServers(ctx, fleet)
 		if err != nil {
 			return ctrl.Result{Requeue: true}, err
 		}
 		fleet.Status.CurrentReplicas = int32(len(servers.Items))
 	}
+	fleet.Status.ReadyReplicas = utils.GetReadyServerCount(servers)
+	fleet.Status.Players, fleet.Status.Capacity = utils.GetPlayerTotals(servers)
+	fleet.Status.Selector = labels.SelectorFromSet(labels.Set{"fleet": fleet.Name}).String()
 
 	if err := r.Status().Update(ctx, fleet); err != nil {
 		return ctrl.Result{Requeue: true}, fmt.Errorf("failed to update Fleet status resource: %w", err)
@@ -109,6 +118,7 @@ func (r *FleetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // scaleServerCount is used to update the server count based on the Fleet spec
 // It either adds more or remove some servers
+// When scaling down, all surplus servers are deleted at once, bounded by the maxConcurrentDrains of the fleet
 func (r *FleetReconciler) scaleServerCount(ctx context.Context, fleet *gameserverv1alpha1.Fleet, namespace string) error {
 	if fleet.Status.CurrentReplicas < fleet.Spec.Scaling.Replicas {
 		//Scale up
@@ -129,15 +139,59 @@ func (r *FleetReconciler) scaleServerCount(ctx context.Context, fleet *gameserve
 		if err != nil {
 			return err
 		}
-		server, err := utils.FindDeleteServer(ctx, fleet, servers, r.Client, r.DeletionChecker)
+		// Terminating servers are already on their way out, so they are not picked again
+		var active, terminating int32
+		for _, server := range servers.Items {
+			if server.DeletionTimestamp != nil {
+				terminating++
+			} else {
+				active++
+			}
+		}
+		surplus := active - fleet.Spec.Scaling.Replicas
+		if limit := fleet.Spec.Scaling.MaxConcurrentDrains; limit != nil {
+			surplus = min(surplus, *limit-terminating)
+		}
+		if surplus <= 0 {
+			return nil
+		}
+		toDelete, err := utils.FindDeleteServers(ctx, fleet, servers, r.Client, r.DeletionChecker, int(surplus))
 		if err != nil {
 			return err
 		}
-		if err := r.Client.Delete(ctx, server); err != nil {
-			r.emitEventf(fleet, corev1.EventTypeWarning, utils.ReasonFleetScaleServers, "Failed to delete a server: %s", err)
+		for _, server := range toDelete {
+			// The servers were picked from the cache, so a server that was allocated since then has a newer version,
+			// and the precondition makes its deletion fail instead. The fleet is requeued and picks again.
+			err := r.Client.Delete(ctx, server, client.Preconditions{ResourceVersion: &server.ResourceVersion})
+			if apierrors.IsConflict(err) {
+				log.FromContext(ctx).Info("Server changed since it was picked for deletion, skipping it", "server", server.Name)
+				continue
+			}
+			if client.IgnoreNotFound(err) != nil {
+				r.emitEventf(fleet, corev1.EventTypeWarning, utils.ReasonFleetScaleServers, "Failed to delete a server: %s", err)
+				return err
+			}
+		}
+		if len(toDelete) > 0 {
+			r.emitEventf(fleet, corev1.EventTypeNormal, utils.ReasonFleetScaleServers, "Scaled servers down to %d", fleet.Spec.Scaling.Replicas)
+		}
+	}
+	return nil
+}
+
+// replaceFailedServers deletes the failed servers of the fleet
+// They still count toward the replicas until they are gone, then the scaling creates their replacements
+func (r *FleetReconciler) replaceFailedServers(ctx context.Context, fleet *gameserverv1alpha1.Fleet, servers *gameserverv1alpha1.ServerList) error {
+	for i := range servers.Items {
+		server := &servers.Items[i]
+		if server.Status.State != gameserverv1alpha1.ServerStateFailed || server.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.Delete(ctx, server); client.IgnoreNotFound(err) != nil {
+			r.emitEventf(fleet, corev1.EventTypeWarning, utils.ReasonFleetServerFailed, "Failed to delete the failed server %s: %s", server.Name, err)
 			return err
 		}
-		r.emitEventf(fleet, corev1.EventTypeNormal, utils.ReasonFleetScaleServers, "Scaled servers down to %d", fleet.Spec.Scaling.Replicas)
+		r.emitEventf(fleet, corev1.EventTypeNormal, utils.ReasonFleetServerFailed, "Replacing the failed server %s", server.Name)
 	}
 	return nil
 }
@@ -25,8 +25,10 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -270,6 +272,193 @@ var _ = Describe("Fleet Controller", func() {
 
 		})
 
+		It("should scale through the scale subresource", func() {
+			reconciler := &FleetReconciler{
+				Client:          k8sClient,
+				Scheme:          k8sClient.Scheme(),
+				Recorder:        NewFakeRecorder(),
+				DeletionChecker: prodChecker,
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reading the replicas and selector")
+			fleet := &gameserverv1alpha1.Fleet{}
+			Expect(k8sClient.Get(ctx, namespacedName, fleet)).To(Succeed())
+			scale := &autoscalingv1.Scale{}
+			Expect(k8sClient.SubResource("scale").Get(ctx, fleet, scale)).To(Succeed())
+			Expect(scale.Spec.Replicas).To(Equal(basicFleetSpec.Scaling.Replicas))
+			Expect(scale.Status.Replicas).To(Equal(basicFleetSpec.Scaling.Replicas))
+			Expect(scale.Status.Selector).To(Equal("fleet=" + FleetName))
+
+			By("Scaling the fleet like kubectl scale")
+			scale.Spec.Replicas = basicFleetSpec.Scaling.Replicas + 1
+			Expect(k8sClient.SubResource("scale").Update(ctx, fleet, client.WithSubResourceBody(scale))).To(Succeed())
+			Expect(k8sClient.Get(ctx, namespacedName, fleet)).To(Succeed())
+			Expect(fleet.Spec.Scaling.Replicas).To(Equal(basicFleetSpec.Scaling.Replicas + 1))
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			serverList := &gameserverv1alpha1.ServerList{}
+			Expect(k8sClient.List(ctx, serverList, client.MatchingLabels{"fleet": FleetName})).To(Succeed())
+			Expect(serverList.Items).To(HaveLen(int(basicFleetSpec.Scaling.Replicas + 1)))
+		})
+
+		It("should replace failed servers", func() {
+			recorder := NewFakeRecorder()
+			reconciler := &FleetReconciler{
+				Client:          k8sClient,
+				Scheme:          k8sClient.Scheme(),
+				Recorder:        recorder,
+				DeletionChecker: prodChecker,
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Failing one of the servers")
+			serverList := &gameserverv1alpha1.ServerList{}
+			Expect(k8sClient.List(ctx, serverList, client.MatchingLabels{"fleet": FleetName})).To(Succeed())
+			Expect(serverList.Items).To(HaveLen(int(basicFleetSpec.Scaling.Replicas)))
+			failed := serverList.Items[0]
+			failed.Status.State = gameserverv1alpha1.ServerStateFailed
+			Expect(k8sClient.Status().Update(ctx, &failed)).To(Succeed())
+
+			By("Deleting the failed server and creating a new one once it is gone")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(&failed), &gameserverv1alpha1.Server{}))
+			}, time.Second*5, time.Millisecond*100).Should(BeTrue())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.List(ctx, serverList, client.MatchingLabels{"fleet": FleetName})).To(Succeed())
+			Expect(serverList.Items).To(HaveLen(int(basicFleetSpec.Scaling.Replicas)))
+			for _, server := range serverList.Items {
+				Expect(server.Name).ToNot(Equal(failed.Name))
+			}
+			hasReplaceEvent := false
+			for _, event := range recorder.Events {
+				if event.Message == "Replacing the failed server "+failed.Name {
+					hasReplaceEvent = true
+					break
+				}
+			}
+			Expect(hasReplaceEvent).To(BeTrue())
+		})
+
+		It("should scale down all surplus servers in one pass, bounded by the max concurrent drains", func() {
+			reconciler := &FleetReconciler{
+				Client:          k8sClient,
+				Scheme:          k8sClient.Scheme(),
+				Recorder:        NewFakeRecorder(),
+				DeletionChecker: prodChecker,
+			}
+			countServers := func() int {
+				serverList := &gameserverv1alpha1.ServerList{}
+				if err := k8sClient.List(ctx, serverList, client.MatchingLabels{"fleet": FleetName}); err != nil {
+					return -1
+				}
+				return len(serverList.Items)
+			}
+
+			var fleet gameserverv1alpha1.Fleet
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, &fleet)).To(Succeed())
+			fleet.Spec.Scaling.Replicas = 6
+			Expect(k8sClient.Update(ctx, &fleet)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(countServers, time.Second*10, time.Millisecond*500).Should(Equal(6))
+
+			By("Keeping two servers terminating")
+			serverList := &gameserverv1alpha1.ServerList{}
+			Expect(k8sClient.List(ctx, serverList, client.MatchingLabels{"fleet": FleetName})).To(Succeed())
+			blocked := serverList.Items[:2]
+			for i := range blocked {
+				controllerutil.AddFinalizer(&blocked[i], "test.falloria.com/block")
+				Expect(k8sClient.Update(ctx, &blocked[i])).To(Succeed())
+				Expect(k8sClient.Delete(ctx, &blocked[i])).To(Succeed())
+			}
+
+			By("Not draining more servers while the limit is reached")
+			limit := int32(2)
+			Expect(k8sClient.Get(ctx, namespacedName, &fleet)).To(Succeed())
+			fleet.Spec.Scaling.Replicas = 1
+			fleet.Spec.Scaling.MaxConcurrentDrains = &limit
+			Expect(k8sClient.Update(ctx, &fleet)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(countServers()).To(Equal(6))
+
+			By("Draining up to the limit once the terminating servers are gone")
+			for i := range blocked {
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: blocked[i].Name, Namespace: FleetNamespace}, &blocked[i])).To(Succeed())
+				controllerutil.RemoveFinalizer(&blocked[i], "test.falloria.com/block")
+				Expect(k8sClient.Update(ctx, &blocked[i])).To(Succeed())
+			}
+			Eventually(countServers, time.Second*10, time.Millisecond*500).Should(Equal(4))
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(countServers, time.Second*10, time.Millisecond*500).Should(Equal(2))
+
+			By("Draining every surplus server at once without a limit")
+			Expect(k8sClient.Get(ctx, namespacedName, &fleet)).To(Succeed())
+			fleet.Spec.Scaling.MaxConcurrentDrains = nil
+			fleet.Spec.Scaling.Replicas = 6
+			Expect(k8sClient.Update(ctx, &fleet)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(countServers, time.Second*10, time.Millisecond*500).Should(Equal(6))
+			Expect(k8sClient.Get(ctx, namespacedName, &fleet)).To(Succeed())
+			fleet.Spec.Scaling.Replicas = 1
+			Expect(k8sClient.Update(ctx, &fleet)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(countServers, time.Second*10, time.Millisecond*500).Should(Equal(1))
+		})
+
+		It("should not delete servers that were allocated after they were listed", func() {
+			reconciler := &FleetReconciler{
+				Client:          k8sClient,
+				Scheme:          k8sClient.Scheme(),
+				Recorder:        NewFakeRecorder(),
+				DeletionChecker: prodChecker,
+			}
+			countServers := func() int {
+				serverList := &gameserverv1alpha1.ServerList{}
+				if err := k8sClient.List(ctx, serverList, client.MatchingLabels{"fleet": FleetName}); err != nil {
+					return -1
+				}
+				return len(serverList.Items)
+			}
+
+			var fleet gameserverv1alpha1.Fleet
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, &fleet)).To(Succeed())
+			fleet.Spec.Scaling.Replicas = 2
+			Expect(k8sClient.Update(ctx, &fleet)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(countServers, time.Second*10, time.Millisecond*500).Should(Equal(2))
+
+			By("Allocating the servers while the fleet scales down")
+			Expect(k8sClient.Get(ctx, namespacedName, &fleet)).To(Succeed())
+			fleet.Spec.Scaling.Replicas = 1
+			Expect(k8sClient.Update(ctx, &fleet)).To(Succeed())
+			reconciler.Client = allocatingClient{Client: k8sClient}
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Consistently(countServers, time.Second, time.Millisecond*200).Should(Equal(2))
+		})
+
 		It("should delete all servers when fleet is deleted", func() {
 			reconciler := &FleetReconciler{
 				Client:          k8sClient,
@@ -390,3 +579,22 @@ var _ = Describe("Fleet Controller", func() {
 		})
 	})
 })
+
+// allocatingClient allocates a server right before it is deleted, like an allocation racing the scale down of a fleet
+type allocatingClient struct {
+	client.Client
+}
+
+func (c allocatingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if _, ok := obj.(*gameserverv1alpha1.Server); ok {
+		server := &gameserverv1alpha1.Server{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), server); err != nil {
+			return err
+		}
+		server.Status.State = gameserverv1alpha1.ServerStateAllocated
+		if err := c.Client.Status().Update(ctx, server); err != nil {
+			return err
+		}
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
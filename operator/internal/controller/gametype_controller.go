@@ -21,10 +21,15 @@ import (
 	"fmt"
 	"github.com/MirrorStudios/fallernetes/internal/utils"
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -47,6 +52,7 @@ const TypeFinalizer = "gametype.falloria.com/finalizer"
 // +kubebuilder:rbac:groups=gameserver.falloria.com,resources=gametypes/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=gameserver.falloria.com,resources=gametypes/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -84,7 +90,12 @@ func (r *GameTypeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	err := r.handleGametypeStatus(ctx, gametype, logger)
+	rolledBack, err := r.handleRollback(ctx, gametype)
+	if err != nil || rolledBack {
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	err = r.handleGametypeStatus(ctx, gametype, logger)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -94,6 +105,11 @@ func (r *GameTypeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{Requeue: true}, err
 	}
 
+	err = r.updateFleetTotals(ctx, gametype, logger)
+	if err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+
 	result, err, done := r.handleUpdating(ctx, gametype, logger)
 	if done {
 		return result, err
@@ -114,9 +130,13 @@ func (r *GameTypeReconciler) updateReplicaCount(ctx context.Context, gametype *g
 		Name:      gametype.Status.CurrentFleetName,
 	}
 	err := r.Get(ctx, name, fleet)
-	if err != nil {
+	if client.IgnoreNotFound(err) != nil {
 		return fmt.Errorf("failed to get fleet to update: %s", err)
 	}
+	if err != nil {
+		// The fleet was removed by the recreate strategy, its replacement is created by handleUpdating
+		return nil
+	}
 
 	if fleet == nil {
 		return fmt.Errorf("could not get fleet to update")
@@ -126,9 +146,34 @@ func (r *GameTypeReconciler) updateReplicaCount(ctx context.Context, gametype *g
 	return err
 }
 
+// updateFleetTotals sums the servers, players and capacity of all fleets of the gametype into its status
+// It also sets the selector of the servers, which the scale subresource reports together with the servers
+func (r *GameTypeReconciler) updateFleetTotals(ctx context.Context, gametype *gameserverv1alpha1.GameType, logger logr.Logger) error {
+	fleets, err := utils.GetFleetsForType(ctx, r.Client, gametype, logger)
+	if err != nil {
+		return err
+	}
+	var replicas, players, capacity int32
+	for _, fleet := range fleets.Items {
+		replicas += fleet.Status.CurrentReplicas
+		players += fleet.Status.Players
+		capacity += fleet.Status.Capacity
+	}
+	selector := labels.SelectorFromSet(labels.Set{"gametype": gametype.Name}).String()
+	if gametype.Status.CurrentReplicas == replicas && gametype.Status.Players == players &&
+		gametype.Status.Capacity == capacity && gametype.Status.Selector == selector {
+		return nil
+	}
+	gametype.Status.CurrentReplicas = replicas
+	gametype.Status.Players = players
+	gametype.Status.Capacity = capacity
+	gametype.Status.Selector = selector
+	return r.Status().Update(ctx, gametype)
+}
+
 // handleUpdating handles the updating process of the GameType
-// Internally, this means creating a fleet, waiting for it to be done
-// Then requesting the other fleet to be deleted
+// Internally, this means creating a fleet matching the spec when needed
+// Then replacing the old fleets with it, based on the update strategy
 // And updating the latest fleets replica counts as needed
 func (r *GameTypeReconciler) handleUpdating(ctx context.Context, gametype *gameserverv1alpha1.GameType, logger logr.Logger) (ctrl.Result, error, bool) {
 	fleets, err := utils.GetFleetsForType(ctx, r.Client, gametype, logger)
@@ -136,54 +181,225 @@ func (r *GameTypeReconciler) handleUpdating(ctx context.Context, gametype *games
 		return ctrl.Result{}, err, true
 	}
 	if len(fleets.Items) == 0 {
-		_, err := r.handleCreation(ctx, gametype, logger)
+		_, err := r.handleCreation(ctx, gametype, gametype.Spec.FleetSpec.Scaling.Replicas, logger)
 		if err != nil {
 			return ctrl.Result{Requeue: true}, err, true
 		}
 		r.emitEvent(gametype, corev1.EventTypeNormal, utils.ReasonGametypeInitialized, "Created initial fleet")
 		return ctrl.Result{Requeue: true}, nil, true
 	}
-	if len(fleets.Items) == 1 {
-		fleet := fleets.Items[0]
-		gametype.Status.CurrentFleetName = fleet.Name
-		if err := r.Status().Update(ctx, gametype); err != nil {
-			return ctrl.Result{Requeue: true}, err, true
+
+	currentFleet, oldFleets := utils.GetFleetsForUpdate(gametype, fleets)
+	if currentFleet == nil {
+		// All fleets are being deleted, the new fleet is created once they are gone
+		if len(oldFleets) == 0 {
+			return ctrl.Result{Requeue: true}, nil, true
 		}
-		if !gameserverv1alpha1.AreFleetsPodsEqual(&fleet.Spec, &gametype.Spec.FleetSpec) {
-			r.emitEvent(gametype, corev1.EventTypeNormal, utils.ReasonGametypeSpecUpdated, "Creating new fleet")
-			res, err := r.handleCreation(ctx, gametype, logger)
-			return res, err, true
-		} else if gametype.Spec.FleetSpec.Scaling.Replicas != gametype.Status.CurrentFleetReplicas {
-			gametype.Status.CurrentFleetReplicas = gametype.Spec.FleetSpec.Scaling.Replicas
-			fleet.Spec.Scaling.Replicas = gametype.Spec.FleetSpec.Scaling.Replicas
-			if err := r.Update(ctx, &fleet); err != nil {
-				return ctrl.Result{Requeue: true}, err, true
-			}
-			err := r.Status().Update(ctx, gametype)
+		r.emitEvent(gametype, corev1.EventTypeNormal, utils.ReasonGametypeSpecUpdated, "Creating new fleet")
+		var replicas int32
+		switch gametype.Spec.Strategy.Type {
+		case gameserverv1alpha1.RecreateStrategy:
+			err := r.deleteFleets(ctx, gametype, oldFleets)
+			return ctrl.Result{Requeue: true}, err, true
+		case gameserverv1alpha1.CanaryStrategy:
+			replicas, err = utils.GetCanaryReplicas(gametype.Spec.FleetSpec.Scaling.Replicas, gametype.Spec.Strategy.Canary)
 			if err != nil {
-				return ctrl.Result{Requeue: true}, err, true
+				r.emitEventf(gametype, corev1.EventTypeWarning, utils.ReasonGametypeCanary, "Invalid canary: %s", err)
+				return ctrl.Result{}, err, true
 			}
-			r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeReplicasUpdated, "Scaling gametype to %d", fleet.Spec.Scaling.Replicas)
 		}
+		res, err := r.handleCreation(ctx, gametype, replicas, logger)
+		return res, err, true
 	}
-	if len(fleets.Items) > 1 {
-		var oldestFleet *gameserverv1alpha1.Fleet
-		for _, fleet := range fleets.Items {
-			if oldestFleet == nil {
-				oldestFleet = &fleet
-			} else if fleet.CreationTimestamp.Before(&oldestFleet.CreationTimestamp) {
-				oldestFleet = &fleet
+
+	// A canary keeps running next to the old fleet, until it is promoted or aborted
+	if len(oldFleets) > 0 && gametype.Spec.Strategy.Type == gameserverv1alpha1.CanaryStrategy &&
+		gametype.Annotations[gameserverv1alpha1.CanaryAnnotation] != gameserverv1alpha1.CanaryPromote {
+		err := r.handleCanary(ctx, gametype, currentFleet, oldFleets)
+		return ctrl.Result{Requeue: true}, err, true
+	}
+
+	if gametype.Status.CurrentFleetName != currentFleet.Name || gametype.Status.CanaryFleetName != "" {
+		promoted := gametype.Status.CanaryFleetName == currentFleet.Name
+		gametype.Status.CurrentFleetName = currentFleet.Name
+		gametype.Status.CanaryFleetName = ""
+		if err := r.Status().Update(ctx, gametype); err != nil {
+			return ctrl.Result{Requeue: true}, err, true
+		}
+		if promoted {
+			r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeCanary, "Canary fleet %s promoted", currentFleet.Name)
+		}
+	}
+
+	if len(oldFleets) > 0 {
+		if gametype.Spec.Strategy.Type == gameserverv1alpha1.RecreateStrategy {
+			err := r.deleteFleets(ctx, gametype, oldFleets)
+			return ctrl.Result{Requeue: true}, err, true
+		}
+		err := r.handleRollingUpdate(ctx, gametype, currentFleet, oldFleets)
+		return ctrl.Result{Requeue: true}, err, true
+	}
+
+	// The update is done, so a promote or abort left on the gametype has nothing to act on anymore
+	// A promotion was already reported when the canary became the current fleet
+	if value, ok := gametype.Annotations[gameserverv1alpha1.CanaryAnnotation]; ok {
+		delete(gametype.Annotations, gameserverv1alpha1.CanaryAnnotation)
+		if err := r.Update(ctx, gametype); err != nil {
+			return ctrl.Result{Requeue: true}, err, true
+		}
+		if value != gameserverv1alpha1.CanaryPromote {
+			r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeCanary, "Ignoring canary %s, no canary fleet is running", value)
+		}
+	}
+
+	if gametype.Spec.FleetSpec.Scaling.Replicas != currentFleet.Spec.Scaling.Replicas {
+		gametype.Status.CurrentFleetReplicas = gametype.Spec.FleetSpec.Scaling.Replicas
+		currentFleet.Spec.Scaling.Replicas = gametype.Spec.FleetSpec.Scaling.Replicas
+		if err := r.Update(ctx, currentFleet); err != nil {
+			return ctrl.Result{Requeue: true}, err, true
+		}
+		err := r.Status().Update(ctx, gametype)
+		if err != nil {
+			return ctrl.Result{Requeue: true}, err, true
+		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeReplicasUpdated, "Scaling gametype to %d", currentFleet.Spec.Scaling.Replicas)
+	}
+	return ctrl.Result{}, nil, false
+}
+
+// handleRollingUpdate moves replicas from the old fleets to the current fleet by one step
+// The old fleets are scaled down oldest first, and deleted once they have no replicas left
+func (r *GameTypeReconciler) handleRollingUpdate(ctx context.Context, gametype *gameserverv1alpha1.GameType, currentFleet *gameserverv1alpha1.Fleet, oldFleets []gameserverv1alpha1.Fleet) error {
+	desired := gametype.Spec.FleetSpec.Scaling.Replicas
+	newReplicas, oldReplicas, err := utils.GetRollingUpdateReplicas(desired, gametype.Spec.Strategy.RollingUpdate, currentFleet, oldFleets)
+	if err != nil {
+		r.emitEventf(gametype, corev1.EventTypeWarning, utils.ReasonGametypeSpecUpdated, "Invalid rolling update: %s", err)
+		return err
+	}
+
+	if currentFleet.Spec.Scaling.Replicas != newReplicas {
+		currentFleet.Spec.Scaling.Replicas = newReplicas
+		if err := r.Update(ctx, currentFleet); err != nil {
+			return fmt.Errorf("failed to scale the new fleet: %w", err)
+		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeReplicasUpdated, "Scaled new fleet %s to %d", currentFleet.Name, newReplicas)
+	}
+
+	var scaleDown int32
+	for _, fleet := range oldFleets {
+		scaleDown += fleet.Spec.Scaling.Replicas
+	}
+	scaleDown -= oldReplicas
+
+	for i := range oldFleets {
+		fleet := &oldFleets[i]
+		remove := min(scaleDown, fleet.Spec.Scaling.Replicas)
+		scaleDown -= remove
+		if fleet.Spec.Scaling.Replicas-remove == 0 {
+			if err := r.deleteFleets(ctx, gametype, oldFleets[i:i+1]); err != nil {
+				return err
 			}
+			continue
+		}
+		if remove == 0 {
+			continue
+		}
+		fleet.Spec.Scaling.Replicas -= remove
+		if err := r.Update(ctx, fleet); err != nil {
+			return fmt.Errorf("failed to scale the old fleet: %w", err)
 		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeReplicasUpdated, "Scaled old fleet %s to %d", fleet.Name, fleet.Spec.Scaling.Replicas)
+	}
+	return nil
+}
 
-		if oldestFleet != nil && oldestFleet.GetDeletionTimestamp() == nil {
-			r.emitEvent(gametype, corev1.EventTypeNormal, utils.ReasonGametypeSpecUpdated, "Deleting extra fleet")
-			if err := r.Delete(ctx, oldestFleet); err != nil {
-				return ctrl.Result{}, err, true
+// handleCanary keeps the canary fleet at its replicas, while the current fleet keeps serving with all replicas
+// The other old fleets are left over from earlier updates, or are a canary superseded by a newer spec, and are deleted
+// When the canary is aborted, the server spec is reverted to the one of the current fleet and the canary fleet is deleted
+func (r *GameTypeReconciler) handleCanary(ctx context.Context, gametype *gameserverv1alpha1.GameType, canaryFleet *gameserverv1alpha1.Fleet, oldFleets []gameserverv1alpha1.Fleet) error {
+	// The newest old fleet only serves when the current fleet is gone
+	stable := len(oldFleets) - 1
+	for i := range oldFleets {
+		if oldFleets[i].Name == gametype.Status.CurrentFleetName {
+			stable = i
+		}
+	}
+	stableFleet := &oldFleets[stable]
+	extraFleets := append(oldFleets[:stable:stable], oldFleets[stable+1:]...)
+
+	if gametype.Annotations[gameserverv1alpha1.CanaryAnnotation] == gameserverv1alpha1.CanaryAbort {
+		gametype.Spec.FleetSpec.ServerSpec = *stableFleet.Spec.ServerSpec.DeepCopy()
+		delete(gametype.Annotations, gameserverv1alpha1.CanaryAnnotation)
+		if err := r.Update(ctx, gametype); err != nil {
+			return fmt.Errorf("failed to revert the gametype spec: %w", err)
+		}
+		if err := r.Delete(ctx, canaryFleet); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete the canary fleet: %w", err)
+		}
+		gametype.Status.CurrentFleetName = stableFleet.Name
+		gametype.Status.CanaryFleetName = ""
+		for _, revision := range gametype.Status.Revisions {
+			if revision.FleetName == stableFleet.Name {
+				gametype.Status.CurrentRevision = revision.Revision
 			}
 		}
+		if err := r.Status().Update(ctx, gametype); err != nil {
+			return err
+		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeCanary, "Canary fleet %s aborted", canaryFleet.Name)
+		return nil
 	}
-	return ctrl.Result{}, nil, false
+
+	desired := gametype.Spec.FleetSpec.Scaling.Replicas
+	canaryReplicas, err := utils.GetCanaryReplicas(desired, gametype.Spec.Strategy.Canary)
+	if err != nil {
+		r.emitEventf(gametype, corev1.EventTypeWarning, utils.ReasonGametypeCanary, "Invalid canary: %s", err)
+		return err
+	}
+	if canaryFleet.Spec.Scaling.Replicas != canaryReplicas {
+		canaryFleet.Spec.Scaling.Replicas = canaryReplicas
+		if err := r.Update(ctx, canaryFleet); err != nil {
+			return fmt.Errorf("failed to scale the canary fleet: %w", err)
+		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeReplicasUpdated, "Scaled canary fleet %s to %d", canaryFleet.Name, canaryReplicas)
+	}
+	if stableFleet.Spec.Scaling.Replicas != desired {
+		stableFleet.Spec.Scaling.Replicas = desired
+		if err := r.Update(ctx, stableFleet); err != nil {
+			return fmt.Errorf("failed to scale the old fleet: %w", err)
+		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeReplicasUpdated, "Scaled old fleet %s to %d", stableFleet.Name, desired)
+	}
+	for i := range extraFleets {
+		if extraFleets[i].Name != gametype.Status.CanaryFleetName {
+			continue
+		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeCanary, "Canary fleet %s superseded by %s", extraFleets[i].Name, canaryFleet.Name)
+	}
+	if err := r.deleteFleets(ctx, gametype, extraFleets); err != nil {
+		return err
+	}
+
+	if gametype.Status.CanaryFleetName != canaryFleet.Name || gametype.Status.CurrentFleetName != stableFleet.Name {
+		gametype.Status.CurrentFleetName = stableFleet.Name
+		gametype.Status.CanaryFleetName = canaryFleet.Name
+		if err := r.Status().Update(ctx, gametype); err != nil {
+			return err
+		}
+		r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeCanary, "Started canary fleet %s with %d replicas", canaryFleet.Name, canaryReplicas)
+	}
+	return nil
+}
+
+// deleteFleets is used to delete the fleets which are replaced by the current fleet
+func (r *GameTypeReconciler) deleteFleets(ctx context.Context, gametype *gameserverv1alpha1.GameType, fleets []gameserverv1alpha1.Fleet) error {
+	for i := range fleets {
+		r.emitEvent(gametype, corev1.EventTypeNormal, utils.ReasonGametypeSpecUpdated, "Deleting extra fleet")
+		if err := r.Delete(ctx, &fleets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -228,17 +444,122 @@ func (r *GameTypeReconciler) handleDeletion(ctx context.Context, gametype *games
 	return nil
 }
 
-// handleCreation is used to initially create the underlying fleet
-func (r *GameTypeReconciler) handleCreation(ctx context.Context, gametype *gameserverv1alpha1.GameType, logger logr.Logger) (ctrl.Result, error) {
+// handleCreation is used to create the underlying fleet with the given amount of replicas
+func (r *GameTypeReconciler) handleCreation(ctx context.Context, gametype *gameserverv1alpha1.GameType, replicas int32, logger logr.Logger) (ctrl.Result, error) {
 	fleet := utils.GetFleetObjectForType(gametype)
+	fleet.Spec.Scaling.Replicas = replicas
+	hash, err := r.ensureControllerRevision(ctx, gametype)
+	if err != nil {
+		logger.Error(err, "failed to store the revision of the new fleet")
+		return ctrl.Result{}, err
+	}
 	if err := r.Create(ctx, fleet); err != nil {
 		r.emitEventf(gametype, corev1.EventTypeWarning, utils.ReasonGametypeReplicasUpdated, "Failed to create new fleet %s", err)
 		logger.Error(err, "failed to create a new fleet for gametype")
 		return ctrl.Result{}, err
 	}
+	dropped := utils.AddRevision(gametype, fleet, hash, metav1.Now())
+	if err := r.Status().Update(ctx, gametype); err != nil {
+		logger.Error(err, "failed to record the revision of the new fleet")
+		return ctrl.Result{}, err
+	}
+	for _, revision := range dropped {
+		controllerRevision := &appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{
+			Namespace: gametype.Namespace,
+			Name:      utils.GetRevisionName(gametype, revision.Hash),
+		}}
+		if err := r.Delete(ctx, controllerRevision); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "failed to delete an old revision", "revision", revision.Revision)
+			return ctrl.Result{}, err
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
+// ensureControllerRevision stores the server spec of the gametype in a ControllerRevision, and returns its hash
+// The ControllerRevision is named after the hash, so it already exists when the gametype rolls back to an earlier spec
+func (r *GameTypeReconciler) ensureControllerRevision(ctx context.Context, gametype *gameserverv1alpha1.GameType) (string, error) {
+	hash, err := utils.GetRevisionHash(&gametype.Spec.FleetSpec.ServerSpec)
+	if err != nil {
+		return "", err
+	}
+	controllerRevision, err := utils.GetControllerRevision(gametype, &gametype.Spec.FleetSpec.ServerSpec, hash)
+	if err != nil {
+		return "", err
+	}
+	err = r.Create(ctx, controllerRevision)
+	if err == nil {
+		return hash, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create controller revision: %w", err)
+	}
+
+	// A revision left over by a deleted gametype of the same name is adopted, so it is not garbage collected
+	existing := &appsv1.ControllerRevision{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(controllerRevision), existing); err != nil {
+		return "", fmt.Errorf("failed to get controller revision: %w", err)
+	}
+	if !metav1.IsControlledBy(existing, gametype) {
+		existing.OwnerReferences = controllerRevision.OwnerReferences
+		if err := r.Update(ctx, existing); err != nil {
+			return "", fmt.Errorf("failed to adopt controller revision: %w", err)
+		}
+	}
+	return hash, nil
+}
+
+// handleRollback reverts the server spec to the revision in the rollback annotation, and removes the annotation
+// The update strategy then replaces the fleet like with any other spec change
+// It returns true when the gametype was updated, so the reconcile can start over
+func (r *GameTypeReconciler) handleRollback(ctx context.Context, gametype *gameserverv1alpha1.GameType) (bool, error) {
+	value, ok := gametype.Annotations[gameserverv1alpha1.RollbackAnnotation]
+	if !ok {
+		return false, nil
+	}
+	delete(gametype.Annotations, gameserverv1alpha1.RollbackAnnotation)
+
+	var revision *gameserverv1alpha1.GameTypeRevision
+	var spec *gameserverv1alpha1.ServerSpec
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		revision = utils.FindRevision(gametype, number)
+	}
+	if revision != nil {
+		revision = revision.DeepCopy()
+		var err error
+		if spec, err = utils.GetRevisionServerSpec(ctx, r.Client, gametype, revision); err != nil {
+			return false, fmt.Errorf("failed to get server spec of revision %d: %w", revision.Revision, err)
+		}
+	}
+	if spec == nil {
+		revision = nil
+		r.emitEventf(gametype, corev1.EventTypeWarning, utils.ReasonGametypeRollback, "Revision %s not found, ignoring rollback", value)
+	} else {
+		gametype.Spec.FleetSpec.ServerSpec = *spec
+	}
+	if err := r.Update(ctx, gametype); err != nil {
+		return false, fmt.Errorf("failed to roll back gametype: %w", err)
+	}
+	if revision == nil {
+		return true, nil
+	}
+
+	// The fleet of the revision becomes the current fleet again when it still exists, so no new revision is recorded
+	fleet := &gameserverv1alpha1.Fleet{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: gametype.Namespace, Name: revision.FleetName}, fleet)
+	if client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("failed to get fleet of revision %d: %w", revision.Revision, err)
+	}
+	if err == nil && fleet.DeletionTimestamp == nil && gametype.Status.CurrentRevision != revision.Revision {
+		gametype.Status.CurrentRevision = revision.Revision
+		if err := r.Status().Update(ctx, gametype); err != nil {
+			return false, fmt.Errorf("failed to update the current revision: %w", err)
+		}
+	}
+	r.emitEventf(gametype, corev1.EventTypeNormal, utils.ReasonGametypeRollback, "Rolling back to revision %d", revision.Revision)
+	return true, nil
+}
+
 // emitEvent is used by the GameTypeReconciler to quickly add new events to objects
 func (r *GameTypeReconciler) emitEvent(object runtime.Object, eventtype string, reason utils.EventReason, message string) {
 	r.Recorder.Event(object, eventtype, string(reason), message)
@@ -250,6 +571,7 @@ func (r *GameTypeReconciler) emitEventf(object runtime.Object, eventtype string,
 }
 
 // handleGametypeStatus is used by the GameTypeReconciler to make sure the fleet in gametype status is the newest one.
+// With the canary strategy the current fleet is kept while it exists, as it keeps serving until a canary is promoted.
 func (r *GameTypeReconciler) handleGametypeStatus(ctx context.Context, gametype *gameserverv1alpha1.GameType, logger logr.Logger) error {
 	fleets, err := utils.GetFleetsForType(ctx, r.Client, gametype, logger)
 	if err != nil {
@@ -257,6 +579,15 @@ func (r *GameTypeReconciler) handleGametypeStatus(ctx context.Context, gametype
 	}
 	var youngestFleet *gameserverv1alpha1.Fleet
 	for _, fleet := range fleets.Items {
+		// handleUpdating moves the current fleet once the canary is promoted
+		if gametype.Spec.Strategy.Type == gameserverv1alpha1.CanaryStrategy &&
+			fleet.Name == gametype.Status.CurrentFleetName && fleet.DeletionTimestamp == nil {
+			return nil
+		}
+		// The canary fleet does not serve the gametype until it is promoted
+		if fleet.Name == gametype.Status.CanaryFleetName {
+			continue
+		}
 		if youngestFleet == nil || fleet.GetCreationTimestamp().After(youngestFleet.GetCreationTimestamp().Time) {
 			youngestFleet = &fleet
 		}
@@ -264,10 +595,6 @@ func (r *GameTypeReconciler) handleGametypeStatus(ctx context.Context, gametype
 
 	if youngestFleet != nil {
 		gametype.Status.CurrentFleetName = youngestFleet.Name
-		err = r.Status().Update(ctx, youngestFleet)
-		if err != nil {
-			return err
-		}
 	}
 	return nil
 }
@@ -24,14 +24,18 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	gameserverv1alpha1 "github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	"github.com/MirrorStudios/fallernetes/internal/utils"
 )
 
 var basicGametypeSpec = gameserverv1alpha1.GameTypeSpec{
@@ -352,6 +356,47 @@ var _ = Describe("GameType Controller", func() {
 			Expect(fleetList.Items[0].Spec.Scaling.Replicas).To(Equal(int32(5)))
 		})
 
+		It("Scales through the scale subresource", func() {
+			reconciler := &GameTypeReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: NewFakeRecorder(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			By("Summing the servers of the fleets")
+			var fleetList gameserverv1alpha1.FleetList
+			Expect(k8sClient.List(ctx, &fleetList, kclient.MatchingLabels{"gametype": resourceName})).To(Succeed())
+			Expect(fleetList.Items).To(HaveLen(1))
+			fleet := fleetList.Items[0]
+			fleet.Status.CurrentReplicas = 2
+			Expect(k8sClient.Status().Update(ctx, &fleet)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var gt gameserverv1alpha1.GameType
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			scale := &autoscalingv1.Scale{}
+			Expect(k8sClient.SubResource("scale").Get(ctx, &gt, scale)).To(Succeed())
+			Expect(scale.Spec.Replicas).To(Equal(basicGametypeSpec.FleetSpec.Scaling.Replicas))
+			Expect(scale.Status.Replicas).To(BeEquivalentTo(2))
+			Expect(scale.Status.Selector).To(Equal("gametype=" + resourceName))
+
+			By("Propagating the scaled replicas to the fleet")
+			scale.Spec.Replicas = 4
+			Expect(k8sClient.SubResource("scale").Update(ctx, &gt, kclient.WithSubResourceBody(scale))).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(4))
+			Expect(k8sClient.List(ctx, &fleetList, kclient.MatchingLabels{"gametype": resourceName})).To(Succeed())
+			Expect(fleetList.Items).To(HaveLen(1))
+			Expect(fleetList.Items[0].Spec.Scaling.Replicas).To(BeEquivalentTo(4))
+		})
+
 		It("Deletes the oldest fleet if multiple fleets exist", func() {
 			By("Initial reconciliation")
 			reconciler := &GameTypeReconciler{
@@ -388,6 +433,310 @@ var _ = Describe("GameType Controller", func() {
 			}, time.Second*5, time.Millisecond*500).Should(BeNumerically("<=", 2))
 		})
 
+		It("Starts a rolling update with an empty fleet", func() {
+			reconciler := &GameTypeReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: NewFakeRecorder(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var gt gameserverv1alpha1.GameType
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Spec.Strategy.Type).To(Equal(gameserverv1alpha1.RollingUpdateStrategy))
+			gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image = "rolling-image"
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var fleetList gameserverv1alpha1.FleetList
+			Expect(k8sClient.List(ctx, &fleetList, kclient.MatchingLabels{"gametype": resourceName})).To(Succeed())
+			Expect(fleetList.Items).To(HaveLen(2))
+			for _, fleet := range fleetList.Items {
+				if fleet.Spec.ServerSpec.Pod.Containers[0].Image == "rolling-image" {
+					Expect(fleet.Spec.Scaling.Replicas).To(BeZero())
+				} else {
+					Expect(fleet.Spec.Scaling.Replicas).To(Equal(gt.Spec.FleetSpec.Scaling.Replicas))
+				}
+			}
+		})
+
+		It("Deletes the old fleet first with the recreate strategy", func() {
+			reconciler := &GameTypeReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: NewFakeRecorder(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var gt gameserverv1alpha1.GameType
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			gt.Spec.Strategy.Type = gameserverv1alpha1.RecreateStrategy
+			gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image = "recreate-image"
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+
+			By("Deleting the old fleet without creating a new one")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			var fleetList gameserverv1alpha1.FleetList
+			Eventually(func() int {
+				_ = k8sClient.List(ctx, &fleetList, kclient.MatchingLabels{"gametype": resourceName})
+				return len(fleetList.Items)
+			}, time.Second*5, time.Millisecond*250).Should(BeZero())
+
+			By("Creating the new fleet once the old one is gone")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.List(ctx, &fleetList, kclient.MatchingLabels{"gametype": resourceName})).To(Succeed())
+			Expect(fleetList.Items).To(HaveLen(1))
+			Expect(fleetList.Items[0].Spec.ServerSpec.Pod.Containers[0].Image).To(Equal("recreate-image"))
+			Expect(fleetList.Items[0].Spec.Scaling.Replicas).To(Equal(gt.Spec.FleetSpec.Scaling.Replicas))
+		})
+
+		It("Keeps the old fleet serving during a canary until it is aborted", func() {
+			reconciler := &GameTypeReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: NewFakeRecorder(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var gt gameserverv1alpha1.GameType
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			oldImage := gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image
+			gt.Spec.Strategy.Type = gameserverv1alpha1.CanaryStrategy
+			gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image = "canary-image"
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+
+			By("Creating the canary fleet next to the old fleet")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var fleetList gameserverv1alpha1.FleetList
+			Expect(k8sClient.List(ctx, &fleetList, kclient.MatchingLabels{"gametype": resourceName})).To(Succeed())
+			Expect(fleetList.Items).To(HaveLen(2))
+			var canaryName, stableName string
+			for _, fleet := range fleetList.Items {
+				if fleet.Spec.ServerSpec.Pod.Containers[0].Image == "canary-image" {
+					canaryName = fleet.Name
+					Expect(fleet.Spec.Scaling.Replicas).To(Equal(int32(1)))
+				} else {
+					stableName = fleet.Name
+					Expect(fleet.Spec.Scaling.Replicas).To(Equal(gt.Spec.FleetSpec.Scaling.Replicas))
+				}
+			}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Status.CanaryFleetName).To(Equal(canaryName))
+			Expect(gt.Status.CurrentFleetName).To(Equal(stableName))
+
+			By("Aborting the canary")
+			gt.Annotations = map[string]string{gameserverv1alpha1.CanaryAnnotation: gameserverv1alpha1.CanaryAbort}
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Annotations).ToNot(HaveKey(gameserverv1alpha1.CanaryAnnotation))
+			Expect(gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image).To(Equal(oldImage))
+			Expect(gt.Status.CanaryFleetName).To(BeEmpty())
+			Expect(gt.Status.CurrentFleetName).To(Equal(stableName))
+			Eventually(func() bool {
+				var fleet gameserverv1alpha1.Fleet
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: canaryName, Namespace: namespace}, &fleet)
+				return errors.IsNotFound(err) || fleet.DeletionTimestamp != nil
+			}, time.Second*5, time.Millisecond*250).Should(BeTrue())
+		})
+
+		It("Keeps the old fleet serving when the spec changes twice during a canary", func() {
+			reconciler := &GameTypeReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: NewFakeRecorder(),
+			}
+			for range 3 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).To(BeNil())
+			}
+
+			var gt gameserverv1alpha1.GameType
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			stableName := gt.Status.CurrentFleetName
+			Expect(stableName).ToNot(BeEmpty())
+			gt.Spec.Strategy.Type = gameserverv1alpha1.CanaryStrategy
+			gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image = "first-canary-image"
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+			for range 3 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).To(BeNil())
+			}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			firstCanary := gt.Status.CanaryFleetName
+			Expect(firstCanary).ToNot(BeEmpty())
+			Expect(gt.Status.CurrentFleetName).To(Equal(stableName))
+
+			By("Changing the spec again while the first canary runs")
+			gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image = "second-canary-image"
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+			for range 3 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).To(BeNil())
+			}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Status.CurrentFleetName).To(Equal(stableName))
+			Expect(gt.Status.CanaryFleetName).ToNot(BeEmpty())
+			Expect(gt.Status.CanaryFleetName).ToNot(Equal(firstCanary))
+
+			var stable gameserverv1alpha1.Fleet
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: stableName, Namespace: namespace}, &stable)).To(Succeed())
+			Expect(stable.DeletionTimestamp).To(BeNil())
+			Expect(stable.Spec.Scaling.Replicas).To(Equal(gt.Spec.FleetSpec.Scaling.Replicas))
+
+			var canary gameserverv1alpha1.Fleet
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: gt.Status.CanaryFleetName, Namespace: namespace}, &canary)).To(Succeed())
+			Expect(canary.Spec.ServerSpec.Pod.Containers[0].Image).To(Equal("second-canary-image"))
+			Eventually(func() bool {
+				var fleet gameserverv1alpha1.Fleet
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: firstCanary, Namespace: namespace}, &fleet)
+				return errors.IsNotFound(err) || fleet.DeletionTimestamp != nil
+			}, time.Second*5, time.Millisecond*250).Should(BeTrue())
+		})
+
+		It("Replaces the old fleet once the canary is promoted", func() {
+			recorder := NewFakeRecorder()
+			reconciler := &GameTypeReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var gt gameserverv1alpha1.GameType
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			replicas := intstr.FromInt32(2)
+			gt.Spec.Strategy = gameserverv1alpha1.UpdateStrategy{
+				Type:   gameserverv1alpha1.CanaryStrategy,
+				Canary: &gameserverv1alpha1.CanaryUpdate{Replicas: &replicas},
+			}
+			gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image = "promoted-image"
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+			for range 3 {
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).To(BeNil())
+			}
+
+			By("Promoting the canary")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			canaryName := gt.Status.CanaryFleetName
+			Expect(canaryName).ToNot(BeEmpty())
+			gt.Annotations = map[string]string{gameserverv1alpha1.CanaryAnnotation: gameserverv1alpha1.CanaryPromote}
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+
+			var fleetList gameserverv1alpha1.FleetList
+			Eventually(func() int {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).To(BeNil())
+				_ = k8sClient.List(ctx, &fleetList, kclient.MatchingLabels{"gametype": resourceName})
+				return len(fleetList.Items)
+			}, time.Second*5, time.Millisecond*250).Should(Equal(1))
+			Expect(fleetList.Items[0].Name).To(Equal(canaryName))
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Annotations).ToNot(HaveKey(gameserverv1alpha1.CanaryAnnotation))
+			Expect(gt.Status.CanaryFleetName).To(BeEmpty())
+			Expect(gt.Status.CurrentFleetName).To(Equal(canaryName))
+
+			promotedEvents := 0
+			for _, event := range recorder.Events {
+				if event.Reason != string(utils.ReasonGametypeCanary) {
+					continue
+				}
+				Expect(event.Message).ToNot(HavePrefix("Ignoring canary"))
+				if event.Message == fmt.Sprintf("Canary fleet %s promoted", canaryName) {
+					promotedEvents++
+				}
+			}
+			Expect(promotedEvents).To(Equal(1))
+		})
+
+		It("Rolls back to an earlier revision", func() {
+			reconciler := &GameTypeReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: NewFakeRecorder(),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			var gt gameserverv1alpha1.GameType
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Status.CurrentRevision).To(Equal(int64(1)))
+			oldImage := gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image
+			gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image = "bad-image"
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Status.CurrentRevision).To(Equal(int64(2)))
+			Expect(gt.Status.Revisions).To(HaveLen(2))
+
+			By("Storing the server specs in controller revisions")
+			for _, revision := range gt.Status.Revisions {
+				controllerRevision := &appsv1.ControllerRevision{}
+				key := types.NamespacedName{Namespace: gt.Namespace, Name: utils.GetRevisionName(&gt, revision.Hash)}
+				Expect(k8sClient.Get(ctx, key, controllerRevision)).To(Succeed())
+				Expect(metav1.IsControlledBy(controllerRevision, &gt)).To(BeTrue())
+			}
+
+			By("Rolling back to the first revision")
+			gt.Annotations = map[string]string{gameserverv1alpha1.RollbackAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Annotations).ToNot(HaveKey(gameserverv1alpha1.RollbackAnnotation))
+			Expect(gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image).To(Equal(oldImage))
+			// The fleet of the first revision still exists, so it is reused instead of recording a new revision
+			Expect(gt.Status.CurrentRevision).To(Equal(int64(1)))
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Status.CurrentRevision).To(Equal(int64(1)))
+			Expect(gt.Status.Revisions).To(HaveLen(2))
+
+			By("Ignoring revisions that are not kept")
+			gt.Annotations = map[string]string{gameserverv1alpha1.RollbackAnnotation: "42"}
+			Expect(k8sClient.Update(ctx, &gt)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &gt)).To(Succeed())
+			Expect(gt.Annotations).ToNot(HaveKey(gameserverv1alpha1.RollbackAnnotation))
+			Expect(gt.Spec.FleetSpec.ServerSpec.Pod.Containers[0].Image).To(Equal(oldImage))
+		})
+
 		It("Should emit the correct events", func() {
 			recorder := NewFakeRecorder()
 			fakeClient := FakeFailClient{
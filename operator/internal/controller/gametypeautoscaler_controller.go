@@ -21,8 +21,16 @@ import (
 	"fmt"
 	"github.com/MirrorStudios/fallernetes/internal/utils"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -33,17 +41,29 @@ import (
 	gameserverv1alpha1 "github.com/MirrorStudios/fallernetes/api/v1alpha1"
 )
 
+// The defaults of the onchange sync, used when they are not set in the spec
+const (
+	ON_CHANGE_DEBOUNCE     = 2 * time.Second
+	ON_CHANGE_MIN_INTERVAL = 10 * time.Second
+)
+
 // GameTypeAutoscalerReconciler reconciles a GameTypeAutoscaler object
 type GameTypeAutoscalerReconciler struct {
 	client.Client
-	Scheme   *runtime.Scheme
-	Webhook  utils.Webhook
-	Recorder record.EventRecorder
+	Scheme     *runtime.Scheme
+	Webhook    utils.Webhook
+	Grpc       utils.GrpcAutoscaler
+	Prometheus utils.PrometheusQuerier
+	Recorder   record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=gameserver.falloria.com,resources=gametypeautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gameserver.falloria.com,resources=gametypeautoscalers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=gameserver.falloria.com,resources=gametypeautoscalers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=gameserver.falloria.com,resources=servers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gameserver.falloria.com,resources=fleets,verbs=get;list;watch;update
+// Autoscalers only read secrets labeled with v1alpha1.AutoscalerSecretLabel, as they send them to addresses of their own choosing
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -57,64 +77,390 @@ func (r *GameTypeAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	gametype := &gameserverv1alpha1.GameType{}
-	namespacedGametype := types.NamespacedName{
-		Name:      autoscaler.Spec.GameTypeName,
-		Namespace: autoscaler.Namespace,
+	//Changes of the target do not sync the onchange autoscaler more often than its min interval
+	if wait := getOnChangeWait(autoscaler, time.Now()); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
 	}
-	if err := r.Get(ctx, namespacedGametype, gametype); err != nil {
-		r.emitEvent(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerInvalidServer, "Failed to find the gametype")
+
+	now := metav1.Now()
+	autoscaler.Status.LastSyncTime = &now
+	result, err := r.sync(ctx, autoscaler)
+	if statusErr := r.Status().Update(ctx, autoscaler); statusErr != nil {
+		logger.Error(statusErr, "Failed to update autoscaler status")
+		if err == nil {
+			return ctrl.Result{Requeue: true}, statusErr
+		}
+	}
+	return result, err
+}
+
+// sync runs a single sync of the autoscaler, and records its outcome in the status of the autoscaler
+func (r *GameTypeAutoscalerReconciler) sync(ctx context.Context, autoscaler *gameserverv1alpha1.GameTypeAutoscaler) (ctrl.Result, error) {
+	target, err := utils.GetScaleTarget(ctx, r.Client, autoscaler)
+	if err != nil {
+		kind := strings.ToLower(string(autoscaler.Spec.GetTarget().Kind))
+		r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerInvalidServer, "Failed to find the %s", kind)
+		r.recordFailure(autoscaler, "TargetNotFound", true, err)
 		return ctrl.Result{Requeue: true}, err
 	}
+	//A fleet of a gametype is scaled by the gametype, which would undo the replicas of the autoscaler
+	if fleet, ok := target.Object.(*gameserverv1alpha1.Fleet); ok {
+		if gametype := utils.GetFleetGameType(fleet); gametype != "" {
+			r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerInvalidTarget,
+				"Fleet %s is managed by gametype %s, target the gametype instead", fleet.Name, gametype)
+			err := fmt.Errorf("fleet %s is managed by gametype %s", fleet.Name, gametype)
+			r.recordFailure(autoscaler, "TargetManagedByGameType", true, err)
+			return ctrl.Result{}, err
+		}
+	}
 
-	//Make sure the type is fine
-	if autoscaler.Spec.AutoscalePolicy.Type != gameserverv1alpha1.Webhook {
+	var result utils.AutoscaleResponse
+	var requeueAfter time.Duration
+	switch autoscaler.Spec.AutoscalePolicy.Type {
+	case gameserverv1alpha1.Webhook:
+		//Send request to defined webhook
+		result, err = r.Webhook.SendScaleWebhookRequest(ctx, autoscaler, target)
+		if err != nil {
+			r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerWebhook, "failed to send the webhook request: %v", err)
+			r.recordFailure(autoscaler, "WebhookFailed", false, err)
+			return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("failed to send scale webhook request: %w", err)
+		}
+	case gameserverv1alpha1.Grpc:
+		result, err = r.Grpc.SendScaleGrpcRequest(ctx, autoscaler, target)
+		if err != nil {
+			r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerGrpc, "failed to send the grpc request: %v", err)
+			r.recordFailure(autoscaler, "GrpcFailed", false, err)
+			return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("failed to send scale grpc request: %w", err)
+		}
+	case gameserverv1alpha1.Buffer:
+		result, err = r.getBufferScale(ctx, autoscaler, target)
+		if err != nil {
+			r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerBuffer, "failed to calculate the buffer: %v", err)
+			r.recordFailure(autoscaler, "BufferFailed", false, err)
+			return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("failed to calculate buffer replicas: %w", err)
+		}
+	case gameserverv1alpha1.Prometheus:
+		result, err = r.getPrometheusScale(ctx, autoscaler, target)
+		if err != nil {
+			r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerPrometheus, "failed to query prometheus: %v", err)
+			r.recordFailure(autoscaler, "PrometheusFailed", false, err)
+			return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("failed to calculate prometheus replicas: %w", err)
+		}
+	case gameserverv1alpha1.Schedule:
+		result, requeueAfter, err = r.getScheduleScale(autoscaler, target, time.Now())
+		if err != nil {
+			r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerSchedule, "failed to evaluate the schedule: %v", err)
+			r.recordFailure(autoscaler, "InvalidSchedule", true, err)
+			return ctrl.Result{}, fmt.Errorf("failed to evaluate schedule: %w", err)
+		}
+	default:
 		r.emitEvent(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerInvalidAutoscalePolicy,
 			"invalid game autoscaler policy type")
-		return ctrl.Result{}, fmt.Errorf("%s is not a valid policy type", autoscaler.Spec.AutoscalePolicy.Type)
+		err := fmt.Errorf("%s is not a valid policy type", autoscaler.Spec.AutoscalePolicy.Type)
+		r.recordFailure(autoscaler, "InvalidPolicy", true, err)
+		return ctrl.Result{}, err
 	}
 
-	//Send request to defined webhook
-	result, err := r.Webhook.SendScaleWebhookRequest(autoscaler, gametype)
-	if err != nil {
-		r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerWebhook, "failed to send the webhook request: %v", err)
-		return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("failed to send scale webhook request: %w", err)
+	//Check that the sync type is fine, the schedule policy syncs on its own boundaries instead
+	if autoscaler.Spec.AutoscalePolicy.Type != gameserverv1alpha1.Schedule {
+		sync := autoscaler.Spec.Sync
+		switch {
+		case sync != nil && sync.Type == gameserverv1alpha1.FixedInterval && sync.Time != nil:
+			requeueAfter = sync.Time.Duration
+		case sync != nil && sync.Type == gameserverv1alpha1.OnChange:
+			//Without an interval, the autoscaler only syncs when the fleets or servers change
+			if sync.Time != nil {
+				requeueAfter = sync.Time.Duration
+			}
+		default:
+			syncType := gameserverv1alpha1.SyncStrategy("missing")
+			if sync != nil {
+				syncType = sync.Type
+			}
+			r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerInvalidSyncType, "%s is not a valid sync type", syncType)
+			err := fmt.Errorf("%s is not a valid sync type, fixed interval requires an interval", syncType)
+			r.recordFailure(autoscaler, "InvalidSync", true, err)
+			return ctrl.Result{}, err
+		}
 	}
 
-	//Check that the sync type is fine
-	if autoscaler.Spec.Sync.Type != gameserverv1alpha1.FixedInterval {
-		r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerInvalidSyncType, "%s is not a valid sync type", autoscaler.Spec.Sync.Type)
-		return ctrl.Result{}, fmt.Errorf("%s is not a valid sync type, currently only fixed interval is supported", autoscaler.Spec.Sync.Type)
+	//If scaling is not requested, the current replicas are recommended, which still have to be within the limits
+	now := time.Now()
+	current := target.Replicas
+	recommendation := current
+	if result.Scale {
+		recommendation = int32(result.DesiredReplicas)
 	}
 
-	//If scaleing not requested, requeue
-	if !result.Scale {
+	//Stabilize the recommendation over the recent ones, and keep it within the limits of the autoscaler
+	autoscaler.Status.LastRecommendation = &recommendation
+	stabilized, reason := utils.StabilizeRecommendation(autoscaler.Spec.Behavior, &autoscaler.Status, current, recommendation, now)
+	if reason != "" {
+		r.emitEventf(autoscaler, corev1.EventTypeNormal, utils.ReasonGameTypeAutoscalerStabilized,
+			"Stabilized recommended replicas from %d to %d by the %s", recommendation, stabilized, reason)
+	}
+	desired, reason, err := utils.ClampReplicas(&autoscaler.Spec, current, stabilized)
+	if err != nil {
+		r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerClamped, "invalid scale limits: %v", err)
+		r.recordFailure(autoscaler, "InvalidLimits", true, err)
+		return ctrl.Result{}, fmt.Errorf("failed to apply scale limits: %w", err)
+	}
+	if reason != "" {
+		r.emitEventf(autoscaler, corev1.EventTypeNormal, utils.ReasonGameTypeAutoscalerClamped,
+			"Clamped recommended replicas from %d to %d by the %s", stabilized, desired, reason)
+	}
+	if desired == current {
+		autoscaler.Status.LastAppliedReplicas = &desired
+		r.recordSuccess(autoscaler)
 		return ctrl.Result{
-			RequeueAfter: autoscaler.Spec.Sync.Time.Duration,
+			RequeueAfter: requeueAfter,
+		}, nil
+	}
+
+	//Wait for the cooldown after the last scale, and sync again once it is over
+	if wait := utils.GetCooldownWait(autoscaler.Spec.Behavior, autoscaler.Status.LastScaleTime, current, desired, now); wait > 0 {
+		r.emitEventf(autoscaler, corev1.EventTypeNormal, utils.ReasonGameTypeAutoscalerStabilized,
+			"Waiting %s for the cooldown before scaling to %d", wait.Round(time.Second), desired)
+		r.recordSuccess(autoscaler)
+		if requeueAfter == 0 || wait < requeueAfter {
+			requeueAfter = wait
+		}
+		return ctrl.Result{
+			RequeueAfter: requeueAfter,
 		}, nil
 	}
 
 	//Otherwise, scale to new replica count
-	gametype.Spec.FleetSpec.Scaling.Replicas = int32(result.DesiredReplicas)
-	if err := r.Client.Update(ctx, gametype); err != nil {
-		r.emitEvent(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerScale, "failed to update the gametype")
-		return ctrl.Result{}, fmt.Errorf("failed to update gametype with new replica count: %w", err)
+	target.SetReplicas(desired)
+	if err := r.Client.Update(ctx, target.Object); err != nil {
+		r.emitEventf(autoscaler, corev1.EventTypeWarning, utils.ReasonGameTypeAutoscalerScale, "failed to update the %s", target.Kind)
+		r.recordFailure(autoscaler, "ScaleFailed", false, err)
+		return ctrl.Result{}, fmt.Errorf("failed to update %s with new replica count: %w", target.Kind, err)
 	}
-	r.emitEventf(autoscaler, corev1.EventTypeNormal, utils.ReasonGameTypeAutoscalerScale, "Scaling game to %d", result.DesiredReplicas)
+	r.emitEventf(autoscaler, corev1.EventTypeNormal, utils.ReasonGameTypeAutoscalerScale, "Scaling game to %d", desired)
+	autoscaler.Status.LastAppliedReplicas = &desired
+	autoscaler.Status.LastScaleTime = &metav1.Time{Time: now}
+	r.recordSuccess(autoscaler)
 
 	//Requeue after the defined time
 	return ctrl.Result{
-		RequeueAfter: autoscaler.Spec.Sync.Time.Duration,
+		RequeueAfter: requeueAfter,
 	}, nil
 }
 
+// recordSuccess resets the failures in the status of the autoscaler after a successful sync
+func (r *GameTypeAutoscalerReconciler) recordSuccess(autoscaler *gameserverv1alpha1.GameTypeAutoscaler) {
+	autoscaler.Status.ConsecutiveFailures = 0
+	autoscaler.Status.LastError = ""
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, metav1.Condition{
+		Type:    gameserverv1alpha1.AutoscalerReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Synced",
+		Message: "The autoscaler synced successfully",
+	})
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, metav1.Condition{
+		Type:    gameserverv1alpha1.AutoscalerDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Synced",
+		Message: "The autoscaler synced successfully",
+	})
+}
+
+// recordFailure records a failed sync in the status of the autoscaler
+// invalid is used for failures caused by the configuration of the autoscaler, which mark it as not ready
+func (r *GameTypeAutoscalerReconciler) recordFailure(autoscaler *gameserverv1alpha1.GameTypeAutoscaler, reason string, invalid bool, err error) {
+	autoscaler.Status.ConsecutiveFailures++
+	autoscaler.Status.LastError = err.Error()
+	readyCondition := metav1.Condition{
+		Type:    gameserverv1alpha1.AutoscalerReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Configured",
+		Message: "The autoscaler is configured correctly",
+	}
+	if invalid {
+		readyCondition.Status = metav1.ConditionFalse
+		readyCondition.Reason = reason
+		readyCondition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, readyCondition)
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, metav1.Condition{
+		Type:    gameserverv1alpha1.AutoscalerDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: fmt.Sprintf("%d syncs failed in a row: %s", autoscaler.Status.ConsecutiveFailures, err),
+	})
+}
+
+// getBufferScale calculates the replicas of the buffer policy from the servers of the target
+// For a gametype, servers that are still occupied in an old fleet during an update count toward the buffer as well
+func (r *GameTypeAutoscalerReconciler) getBufferScale(ctx context.Context, autoscaler *gameserverv1alpha1.GameTypeAutoscaler, target *utils.ScaleTarget) (utils.AutoscaleResponse, error) {
+	servers := &gameserverv1alpha1.ServerList{}
+	if err := r.List(ctx, servers, client.InNamespace(target.Namespace), client.MatchingLabels(target.ServerLabels)); err != nil {
+		return utils.AutoscaleResponse{}, err
+	}
+	desired, err := utils.GetBufferReplicas(autoscaler.Spec.AutoscalePolicy.BufferAutoscalerSpec, utils.GetOccupiedServerCount(servers))
+	if err != nil {
+		return utils.AutoscaleResponse{}, err
+	}
+	return utils.AutoscaleResponse{
+		Scale:           desired != target.Replicas,
+		DesiredReplicas: int(desired),
+	}, nil
+}
+
+// getPrometheusScale calculates the replicas of the prometheus policy from the value of its query
+func (r *GameTypeAutoscalerReconciler) getPrometheusScale(ctx context.Context, autoscaler *gameserverv1alpha1.GameTypeAutoscaler, target *utils.ScaleTarget) (utils.AutoscaleResponse, error) {
+	spec := autoscaler.Spec.AutoscalePolicy.PrometheusAutoscalerSpec
+	value, err := r.Prometheus.Query(ctx, spec)
+	if err != nil {
+		return utils.AutoscaleResponse{}, err
+	}
+	desired, err := utils.GetPrometheusReplicas(spec, value)
+	if err != nil {
+		return utils.AutoscaleResponse{}, err
+	}
+	return utils.AutoscaleResponse{
+		Scale:           desired != target.Replicas,
+		DesiredReplicas: int(desired),
+	}, nil
+}
+
+// getScheduleScale evaluates the schedule policy at now, and returns how long to wait until the next schedule boundary
+func (r *GameTypeAutoscalerReconciler) getScheduleScale(autoscaler *gameserverv1alpha1.GameTypeAutoscaler, target *utils.ScaleTarget, now time.Time) (utils.AutoscaleResponse, time.Duration, error) {
+	schedule, err := utils.GetScheduledReplicas(autoscaler.Spec.AutoscalePolicy.ScheduleAutoscalerSpec, now)
+	if err != nil {
+		return utils.AutoscaleResponse{}, 0, err
+	}
+	// Cron schedules always fire again, this only guards against waiting forever
+	requeueAfter := time.Hour
+	if !schedule.Next.IsZero() {
+		requeueAfter = max(time.Second, schedule.Next.Sub(now))
+	}
+	return utils.AutoscaleResponse{
+		Scale:           schedule.Active && schedule.Replicas != target.Replicas,
+		DesiredReplicas: int(schedule.Replicas),
+	}, requeueAfter, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
+// Fleets and servers are watched for the autoscalers that use the onchange sync
 func (r *GameTypeAutoscalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&gameserverv1alpha1.GameTypeAutoscaler{}).
+		For(&gameserverv1alpha1.GameTypeAutoscaler{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&gameserverv1alpha1.Fleet{}, &onChangeHandler{reconciler: r, changed: fleetChanged}).
+		Watches(&gameserverv1alpha1.Server{}, &onChangeHandler{reconciler: r, changed: serverChanged}).
 		Complete(r)
 }
 
+// getOnChangeWait returns how long the onchange autoscaler has to wait before it can sync again
+// Other sync types never have to wait
+func getOnChangeWait(autoscaler *gameserverv1alpha1.GameTypeAutoscaler, now time.Time) time.Duration {
+	sync := autoscaler.Spec.Sync
+	if sync == nil || sync.Type != gameserverv1alpha1.OnChange || autoscaler.Status.LastSyncTime == nil {
+		return 0
+	}
+	minInterval := ON_CHANGE_MIN_INTERVAL
+	if sync.MinInterval != nil {
+		minInterval = sync.MinInterval.Duration
+	}
+	return autoscaler.Status.LastSyncTime.Add(minInterval).Sub(now)
+}
+
+// findOnChangeAutoscalers finds the autoscalers using the onchange sync that target the fleet or server
+func (r *GameTypeAutoscalerReconciler) findOnChangeAutoscalers(ctx context.Context, object client.Object) ([]gameserverv1alpha1.GameTypeAutoscaler, error) {
+	autoscalers := &gameserverv1alpha1.GameTypeAutoscalerList{}
+	if err := r.List(ctx, autoscalers, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil, err
+	}
+	var result []gameserverv1alpha1.GameTypeAutoscaler
+	for _, autoscaler := range autoscalers.Items {
+		sync := autoscaler.Spec.Sync
+		if sync != nil && sync.Type == gameserverv1alpha1.OnChange && isTargetOf(autoscaler.Spec.GetTarget(), object) {
+			result = append(result, autoscaler)
+		}
+	}
+	return result, nil
+}
+
+// isTargetOf returns true if the fleet or server belongs to the target of an autoscaler
+func isTargetOf(target gameserverv1alpha1.TargetRef, object client.Object) bool {
+	switch target.Kind {
+	case gameserverv1alpha1.GameTypeTarget:
+		return object.GetLabels()["gametype"] == target.Name
+	case gameserverv1alpha1.FleetTarget:
+		if _, ok := object.(*gameserverv1alpha1.Fleet); ok {
+			return object.GetName() == target.Name
+		}
+		return object.GetLabels()["fleet"] == target.Name
+	}
+	return false
+}
+
+// fleetChanged returns true if the server or player counts of the fleet changed
+func fleetChanged(oldObject, newObject client.Object) bool {
+	oldFleet, okOld := oldObject.(*gameserverv1alpha1.Fleet)
+	newFleet, okNew := newObject.(*gameserverv1alpha1.Fleet)
+	if !okOld || !okNew {
+		return false
+	}
+	return oldFleet.Status.CurrentReplicas != newFleet.Status.CurrentReplicas ||
+		oldFleet.Status.ReadyReplicas != newFleet.Status.ReadyReplicas ||
+		oldFleet.Status.Players != newFleet.Status.Players ||
+		oldFleet.Status.Capacity != newFleet.Status.Capacity
+}
+
+// serverChanged returns true if the state or player count of the server changed, or it started deleting
+func serverChanged(oldObject, newObject client.Object) bool {
+	oldServer, okOld := oldObject.(*gameserverv1alpha1.Server)
+	newServer, okNew := newObject.(*gameserverv1alpha1.Server)
+	if !okOld || !okNew {
+		return false
+	}
+	return oldServer.Status.State != newServer.Status.State ||
+		oldServer.Status.Players != newServer.Status.Players ||
+		(oldServer.DeletionTimestamp == nil) != (newServer.DeletionTimestamp == nil)
+}
+
+// onChangeHandler enqueues the onchange autoscalers of a target when its fleets or servers change
+// The requests are delayed by the debounce of the autoscaler, so a burst of changes only causes a single sync
+type onChangeHandler struct {
+	reconciler *GameTypeAutoscalerReconciler
+	changed    func(oldObject, newObject client.Object) bool
+}
+
+func (h *onChangeHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(ctx, e.Object, q)
+}
+
+func (h *onChangeHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if h.changed(e.ObjectOld, e.ObjectNew) {
+		h.enqueue(ctx, e.ObjectNew, q)
+	}
+}
+
+func (h *onChangeHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(ctx, e.Object, q)
+}
+
+func (h *onChangeHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+// enqueue adds the onchange autoscalers of the object to the queue after their debounce
+func (h *onChangeHandler) enqueue(ctx context.Context, object client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	autoscalers, err := h.reconciler.findOnChangeAutoscalers(ctx, object)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to find the autoscalers of the changed object", "object", object.GetName())
+		return
+	}
+	for _, autoscaler := range autoscalers {
+		debounce := ON_CHANGE_DEBOUNCE
+		if autoscaler.Spec.Sync.Debounce != nil {
+			debounce = autoscaler.Spec.Sync.Debounce.Duration
+		}
+		q.AddAfter(reconcile.Request{NamespacedName: types.NamespacedName{Name: autoscaler.Name, Namespace: autoscaler.Namespace}}, debounce)
+	}
+}
+
 // emitEvent is used by the GameTypeAutoscalerReconciler to easily add events to objects
 func (r *GameTypeAutoscalerReconciler) emitEvent(object runtime.Object, eventtype string, reason utils.EventReason, message string) {
 	r.Recorder.Event(object, eventtype, string(reason), message)
@@ -20,12 +20,22 @@ import (
 	"context"
 	"fmt"
 	"github.com/MirrorStudios/fallernetes/internal/utils"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"time"
 
+	autoscalerv1 "github.com/MirrorStudios/fallernetes/api/proto/autoscaler/v1"
+	"google.golang.org/grpc"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,7 +49,7 @@ type TestWebhook struct {
 	Error    bool
 }
 
-func (t *TestWebhook) SendScaleWebhookRequest(autoscaler *gameserverv1alpha1.GameTypeAutoscaler, gametype *gameserverv1alpha1.GameType) (utils.AutoscaleResponse, error) {
+func (t *TestWebhook) SendScaleWebhookRequest(ctx context.Context, autoscaler *gameserverv1alpha1.GameTypeAutoscaler, target *utils.ScaleTarget) (utils.AutoscaleResponse, error) {
 	if t.Error {
 		return utils.AutoscaleResponse{}, fmt.Errorf("random error with webhook")
 	}
@@ -49,6 +59,15 @@ func (t *TestWebhook) SendScaleWebhookRequest(autoscaler *gameserverv1alpha1.Gam
 	}, nil
 }
 
+type TestGrpcAutoscaler struct {
+	autoscalerv1.UnimplementedAutoscalerServer
+	Replicas int32
+}
+
+func (t *TestGrpcAutoscaler) Scale(ctx context.Context, request *autoscalerv1.ScaleRequest) (*autoscalerv1.ScaleResponse, error) {
+	return &autoscalerv1.ScaleResponse{Scale: true, DesiredReplicas: t.Replicas}, nil
+}
+
 var duration = metav1.Duration{Duration: 5 * time.Second}
 var path = "/scale"
 
@@ -59,7 +78,7 @@ var basicGameTypeAutoscaler = gameserverv1alpha1.GameTypeAutoscalerSpec{
 	GameTypeName: resourceName,
 	AutoscalePolicy: gameserverv1alpha1.AutoscalePolicy{
 		Type: gameserverv1alpha1.Webhook,
-		WebhookAutoscalerSpec: gameserverv1alpha1.WebhookAutoscalerSpec{
+		WebhookAutoscalerSpec: &gameserverv1alpha1.WebhookAutoscalerSpec{
 			Path: &path,
 			Service: &gameserverv1alpha1.Service{
 				Name:      "some-random-service",
@@ -68,7 +87,7 @@ var basicGameTypeAutoscaler = gameserverv1alpha1.GameTypeAutoscalerSpec{
 			},
 		},
 	},
-	Sync: gameserverv1alpha1.Sync{
+	Sync: &gameserverv1alpha1.Sync{
 		Type: gameserverv1alpha1.FixedInterval,
 		Time: &duration,
 	},
@@ -302,6 +321,504 @@ var _ = Describe("GameTypeAutoscaler Controller", func() {
 			Expect(err).ToNot(BeNil())
 		})
 
+		It("Reconcile with the buffer policy", func() {
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  &TestWebhook{},
+				Recorder: NewFakeRecorder(),
+			}
+
+			By("Creating servers of the gametype with players on one of them")
+			var servers []*gameserverv1alpha1.Server
+			for i := range 3 {
+				server := &gameserverv1alpha1.Server{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("buffer-server-%d", i),
+						Namespace: namespace,
+						Labels:    map[string]string{"gametype": resourceName},
+					},
+					Spec: basicServerSpec,
+				}
+				Expect(k8sClient.Create(ctx, server)).To(Succeed())
+				servers = append(servers, server)
+			}
+			defer func() {
+				for _, server := range servers {
+					Expect(k8sClient.Delete(ctx, server)).To(Succeed())
+				}
+			}()
+			servers[0].Status.Players = 4
+			Expect(k8sClient.Status().Update(ctx, servers[0])).To(Succeed())
+			servers[1].Status.State = gameserverv1alpha1.ServerStateAllocated
+			Expect(k8sClient.Status().Update(ctx, servers[1])).To(Succeed())
+
+			By("Switching the autoscaler to the buffer policy")
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.AutoscalePolicy = gameserverv1alpha1.AutoscalePolicy{
+				Type: gameserverv1alpha1.Buffer,
+				BufferAutoscalerSpec: &gameserverv1alpha1.BufferAutoscalerSpec{
+					BufferSize: intstr.FromInt32(3),
+				},
+			}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			res, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(BeEquivalentTo(5 * time.Second))
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(5))
+
+			By("Failing without a buffer spec")
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.AutoscalePolicy.BufferAutoscalerSpec = nil
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Reconcile with the prometheus policy", func() {
+			By("Starting a stand-in for the query api of prometheus")
+			var queries []string
+			prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queries = append(queries, r.FormValue("query"))
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"130"]}]}}`))
+			}))
+			defer prometheus.Close()
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:     k8sClient,
+				Scheme:     k8sClient.Scheme(),
+				Webhook:    &TestWebhook{},
+				Prometheus: utils.ProductionPrometheusQuery{},
+				Recorder:   NewFakeRecorder(),
+			}
+
+			By("Switching the autoscaler to the prometheus policy")
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.AutoscalePolicy = gameserverv1alpha1.AutoscalePolicy{
+				Type: gameserverv1alpha1.Prometheus,
+				PrometheusAutoscalerSpec: &gameserverv1alpha1.PrometheusAutoscalerSpec{
+					ServerUrl:             prometheus.URL,
+					Query:                 `sum(game_players{game="test-resource"})`,
+					TargetValuePerReplica: resource.MustParse("20"),
+				},
+			}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			res, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(BeEquivalentTo(5 * time.Second))
+			Expect(queries).To(Equal([]string{`sum(game_players{game="test-resource"})`}))
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(7))
+
+			By("Failing when prometheus is not reachable")
+			prometheus.Close()
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerDegraded)).To(BeTrue())
+		})
+
+		It("Reconcile with the grpc policy", func() {
+			By("Starting an in-process grpc autoscaler")
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			grpcServer := grpc.NewServer()
+			autoscalerv1.RegisterAutoscalerServer(grpcServer, &TestGrpcAutoscaler{Replicas: 6})
+			go func() {
+				_ = grpcServer.Serve(listener)
+			}()
+			defer grpcServer.Stop()
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  &TestWebhook{},
+				Grpc:     utils.ProductionGrpcRequest{Client: k8sClient},
+				Recorder: NewFakeRecorder(),
+			}
+
+			By("Switching the autoscaler to the grpc policy")
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.AutoscalePolicy = gameserverv1alpha1.AutoscalePolicy{
+				Type:               gameserverv1alpha1.Grpc,
+				GrpcAutoscalerSpec: &gameserverv1alpha1.GrpcAutoscalerSpec{Address: listener.Addr().String()},
+			}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			res, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(BeEquivalentTo(5 * time.Second))
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(6))
+
+			By("Failing when the autoscaler is not reachable")
+			grpcServer.Stop()
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Reconcile with the schedule policy", func() {
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  &TestWebhook{},
+				Recorder: NewFakeRecorder(),
+			}
+
+			By("Switching the autoscaler to a schedule that is always active")
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.Sync = nil
+			autoscaler.Spec.AutoscalePolicy = gameserverv1alpha1.AutoscalePolicy{
+				Type: gameserverv1alpha1.Schedule,
+				ScheduleAutoscalerSpec: &gameserverv1alpha1.ScheduleAutoscalerSpec{
+					Entries: []gameserverv1alpha1.ScheduleEntry{{Cron: "* * * * *", Replicas: 7}},
+				},
+			}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			res, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(BeNumerically("<=", time.Minute))
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(7))
+
+			By("Failing on an invalid cron expression")
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.AutoscalePolicy.ScheduleAutoscalerSpec.Entries[0].Cron = "every minute"
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+
+			By("Requiring a sync for the other policies")
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.AutoscalePolicy = basicGameTypeAutoscaler.AutoscalePolicy
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("Clamps recommendations to the limits of the autoscaler", func() {
+			recorder := NewFakeRecorder()
+			hook := &TestWebhook{Scale: true, Replicas: 10000}
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  hook,
+				Recorder: recorder,
+			}
+
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			minReplicas := int32(1)
+			maxReplicas := int32(20)
+			autoscaler.Spec.MinReplicas = &minReplicas
+			autoscaler.Spec.MaxReplicas = &maxReplicas
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			By("Clamping to the max replicas")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(20))
+			hasClampEvent := false
+			for _, event := range recorder.Events {
+				if event.Message == "Clamped recommended replicas from 10000 to 20 by the max replicas of 20" {
+					hasClampEvent = true
+					break
+				}
+			}
+			Expect(hasClampEvent).To(BeTrue())
+
+			By("Clamping to the min replicas")
+			hook.Replicas = 0
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(1))
+
+			By("Clamping the current replicas when no scaling is requested")
+			hook.Scale = false
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			minReplicas = 5
+			autoscaler.Spec.MinReplicas = &minReplicas
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(5))
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(*autoscaler.Status.LastRecommendation).To(BeEquivalentTo(1))
+		})
+
+		It("Stabilizes the recommendations with the behavior", func() {
+			recorder := NewFakeRecorder()
+			hook := &TestWebhook{Scale: true, Replicas: 6}
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  hook,
+				Recorder: recorder,
+			}
+
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.Behavior = &gameserverv1alpha1.AutoscalerBehavior{
+				ScaleUp:   &gameserverv1alpha1.ScalingRules{Cooldown: &metav1.Duration{Duration: time.Hour}},
+				ScaleDown: &gameserverv1alpha1.ScalingRules{StabilizationWindow: &metav1.Duration{Duration: time.Hour}},
+			}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			By("Scaling up without a previous scale")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(6))
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(autoscaler.Status.LastScaleTime).ToNot(BeNil())
+
+			By("Keeping the replicas while the higher recommendation is in the scale down window")
+			hook.Replicas = 3
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(6))
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(autoscaler.Status.Recommendations).To(HaveLen(2))
+			Expect(*autoscaler.Status.LastRecommendation).To(BeEquivalentTo(3))
+
+			By("Waiting for the cooldown before scaling up again")
+			hook.Replicas = 9
+			res, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(BeEquivalentTo(5 * time.Second))
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(6))
+			hasCooldownEvent := false
+			for _, event := range recorder.Events {
+				if event.Reason == string(utils.ReasonGameTypeAutoscalerStabilized) &&
+					strings.HasPrefix(event.Message, "Waiting") {
+					hasCooldownEvent = true
+					break
+				}
+			}
+			Expect(hasCooldownEvent).To(BeTrue())
+
+			By("Applying recommendations right away without the behavior")
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.Behavior = nil
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			hook.Replicas = 3
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(3))
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(autoscaler.Status.Recommendations).To(BeEmpty())
+		})
+
+		It("Records the outcome of every sync in the status", func() {
+			hook := &TestWebhook{Scale: true, Replicas: 4}
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  hook,
+				Recorder: NewFakeRecorder(),
+			}
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+
+			By("Recording a successful sync")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(autoscaler.Status.LastSyncTime).ToNot(BeNil())
+			Expect(*autoscaler.Status.LastRecommendation).To(BeEquivalentTo(4))
+			Expect(*autoscaler.Status.LastAppliedReplicas).To(BeEquivalentTo(4))
+			Expect(meta.IsStatusConditionTrue(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerDegraded)).To(BeTrue())
+
+			By("Counting failing webhook requests")
+			hook.Error = true
+			for range 2 {
+				_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+				Expect(err).ToNot(BeNil())
+			}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(autoscaler.Status.ConsecutiveFailures).To(BeEquivalentTo(2))
+			Expect(autoscaler.Status.LastError).To(ContainSubstring("random error with webhook"))
+			Expect(meta.IsStatusConditionTrue(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerDegraded)).To(BeTrue())
+
+			By("Resetting the failures after a successful sync")
+			hook.Error = false
+			hook.Scale = false
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(autoscaler.Status.ConsecutiveFailures).To(BeZero())
+			Expect(autoscaler.Status.LastError).To(BeEmpty())
+			Expect(meta.IsStatusConditionFalse(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerDegraded)).To(BeTrue())
+
+			By("Marking an invalid configuration as not ready")
+			autoscaler.Spec.GameTypeName = "missing-gametype"
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerReady)).To(BeTrue())
+		})
+
+		It("Syncs on changes with the onchange sync", func() {
+			hook := &TestWebhook{Scale: true, Replicas: 4}
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  hook,
+				Recorder: NewFakeRecorder(),
+			}
+
+			By("Switching the autoscaler to the onchange sync")
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.Sync = &gameserverv1alpha1.Sync{
+				Type:        gameserverv1alpha1.OnChange,
+				MinInterval: &metav1.Duration{Duration: time.Hour},
+			}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			By("Syncing without a periodic requeue")
+			res, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(BeZero())
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(4))
+
+			By("Waiting for the min interval before syncing again")
+			hook.Replicas = 6
+			res, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			Expect(res.RequeueAfter).To(BeNumerically(">", 59*time.Minute))
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(4))
+
+			By("Mapping the servers of the gametype to the autoscaler")
+			server := &gameserverv1alpha1.Server{ObjectMeta: metav1.ObjectMeta{
+				Name:      "onchange-server",
+				Namespace: namespace,
+				Labels:    map[string]string{"gametype": resourceName},
+			}}
+			autoscalers, err := controllerReconciler.findOnChangeAutoscalers(ctx, server)
+			Expect(err).To(BeNil())
+			Expect(autoscalers).To(HaveLen(1))
+			server.Labels["gametype"] = "other-game"
+			autoscalers, err = controllerReconciler.findOnChangeAutoscalers(ctx, server)
+			Expect(err).To(BeNil())
+			Expect(autoscalers).To(BeEmpty())
+
+			By("Only reacting to changes of the counts")
+			changedServer := server.DeepCopy()
+			Expect(serverChanged(server, changedServer)).To(BeFalse())
+			changedServer.Status.Players = 3
+			Expect(serverChanged(server, changedServer)).To(BeTrue())
+			fleet := &gameserverv1alpha1.Fleet{}
+			changedFleet := fleet.DeepCopy()
+			changedFleet.Labels = map[string]string{"changed": "true"}
+			Expect(fleetChanged(fleet, changedFleet)).To(BeFalse())
+			changedFleet.Status.ReadyReplicas = 2
+			Expect(fleetChanged(fleet, changedFleet)).To(BeTrue())
+		})
+
+		It("Reconcile with a fleet target", func() {
+			hook := &TestWebhook{Scale: true, Replicas: 7}
+			controllerReconciler := &GameTypeAutoscalerReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Webhook:  hook,
+				Recorder: NewFakeRecorder(),
+			}
+
+			By("Creating a standalone fleet")
+			fleetNamespacedName := types.NamespacedName{Name: "autoscaled-fleet", Namespace: namespace}
+			fleet := &gameserverv1alpha1.Fleet{
+				ObjectMeta: metav1.ObjectMeta{Name: fleetNamespacedName.Name, Namespace: namespace},
+				Spec:       basicFleetSpec,
+			}
+			Expect(k8sClient.Create(ctx, fleet)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, fleet)).To(Succeed())
+			}()
+
+			By("Targeting the fleet")
+			autoscaler := &gameserverv1alpha1.GameTypeAutoscaler{}
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.TargetRef = &gameserverv1alpha1.TargetRef{
+				Kind: gameserverv1alpha1.FleetTarget,
+				Name: fleetNamespacedName.Name,
+			}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+
+			By("Scaling the fleet instead of the gametype")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).To(BeNil())
+			updatedFleet := gameserverv1alpha1.Fleet{}
+			Expect(k8sClient.Get(ctx, fleetNamespacedName, &updatedFleet)).To(Succeed())
+			Expect(updatedFleet.Spec.Scaling.Replicas).To(BeEquivalentTo(7))
+			updatedGameType := gameserverv1alpha1.GameType{}
+			Expect(k8sClient.Get(ctx, gameTypeNamespacedName, &updatedGameType)).To(Succeed())
+			Expect(updatedGameType.Spec.FleetSpec.Scaling.Replicas).To(Equal(basicGametypeSpec.FleetSpec.Scaling.Replicas))
+
+			By("Refusing to scale a fleet that is managed by a gametype")
+			Expect(k8sClient.Get(ctx, fleetNamespacedName, &updatedFleet)).To(Succeed())
+			updatedFleet.Labels = map[string]string{"gametype": resourceName}
+			Expect(k8sClient.Update(ctx, &updatedFleet)).To(Succeed())
+			hook.Replicas = 2
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+			Expect(k8sClient.Get(ctx, fleetNamespacedName, &updatedFleet)).To(Succeed())
+			Expect(updatedFleet.Spec.Scaling.Replicas).To(BeEquivalentTo(7))
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			ready := meta.FindStatusCondition(autoscaler.Status.Conditions, gameserverv1alpha1.AutoscalerReady)
+			Expect(ready).ToNot(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("TargetManagedByGameType"))
+
+			By("Failing when the fleet does not exist")
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.TargetRef.Name = "missing-fleet"
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: autoscalerNamespacedName})
+			Expect(err).ToNot(BeNil())
+
+			By("Mapping the servers of the fleet to the autoscaler")
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, autoscaler)).To(Succeed())
+			autoscaler.Spec.TargetRef.Name = fleetNamespacedName.Name
+			autoscaler.Spec.Sync = &gameserverv1alpha1.Sync{Type: gameserverv1alpha1.OnChange}
+			Expect(k8sClient.Update(ctx, autoscaler)).To(Succeed())
+			server := &gameserverv1alpha1.Server{ObjectMeta: metav1.ObjectMeta{
+				Name:      "fleet-target-server",
+				Namespace: namespace,
+				Labels:    map[string]string{"fleet": fleetNamespacedName.Name, "gametype": resourceName},
+			}}
+			autoscalers, err := controllerReconciler.findOnChangeAutoscalers(ctx, server)
+			Expect(err).To(BeNil())
+			Expect(autoscalers).To(HaveLen(1))
+			server.Labels["fleet"] = "other-fleet"
+			autoscalers, err = controllerReconciler.findOnChangeAutoscalers(ctx, server)
+			Expect(err).To(BeNil())
+			Expect(autoscalers).To(BeEmpty())
+		})
+
 		It("should fail update", func() {
 			fakeClient := FakeFailClient{
 				client:       k8sClient,
@@ -380,6 +897,7 @@ var _ = Describe("GameTypeAutoscaler Controller", func() {
 			Expect(hasGametypeErrorEvent).To(BeTrue())
 
 			By("Reset game name")
+			Expect(k8sClient.Get(ctx, autoscalerNamespacedName, GameTypeAutoscaler)).To(Succeed())
 			GameTypeAutoscaler.Spec.GameTypeName = originalGametype
 			err = k8sClient.Update(ctx, GameTypeAutoscaler)
 			Expect(err).To(BeNil())
@@ -22,12 +22,15 @@ import (
 	"fmt"
 	"github.com/MirrorStudios/fallernetes/internal/utils"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
 
 	gameserverv1alpha1 "github.com/MirrorStudios/fallernetes/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,6 +40,15 @@ import (
 
 const SERVER_FINALIZER = "server.falloria.com/finalizer"
 
+// PLAYER_SYNC_INTERVAL is how often the player count and the heartbeat of a ready server are read from the sidecar in poll mode
+const PLAYER_SYNC_INTERVAL = 10 * time.Second
+
+// REPLACE_BACKOFF is how long a replaced pod waits before it is created again, it doubles with every replacement
+const REPLACE_BACKOFF = 10 * time.Second
+
+// MAX_REPLACE_BACKOFF caps the backoff of replaced pods, like the crash loop backoff of the kubelet
+const MAX_REPLACE_BACKOFF = 5 * time.Minute
+
 // ServerReconciler reconciles a Server object
 type ServerReconciler struct {
 	client.Client
@@ -44,6 +56,8 @@ type ServerReconciler struct {
 	Scheme            *runtime.Scheme
 	Recorder          record.EventRecorder
 	DeletionAllowed   utils.Deletion
+	PlayerCounter     utils.PlayerCounter
+	HeartbeatChecker  utils.HeartbeatChecker
 }
 
 // +kubebuilder:rbac:groups=gameserver.falloria.com,resources=servers,verbs=get;list;watch;create;update;patch;delete
@@ -51,6 +65,8 @@ type ServerReconciler struct {
 // +kubebuilder:rbac:groups=gameserver.falloria.com,resources=servers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -93,6 +109,7 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 
 	// Ensure Pod exists
+	previousState := server.Status.State
 	podExists, err := r.ensurePodExists(ctx, server)
 	if err != nil {
 		if err := r.Update(ctx, server); err != nil {
@@ -102,19 +119,73 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 	if !podExists {
 		// If a Pod was created, exit early to requeue the reconciliation
+		if err := r.updateState(ctx, server, previousState); err != nil {
+			return ctrl.Result{}, err
+		}
+		if wait := getReplaceWait(server, time.Now()); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: server.Namespace, Name: server.Name + "-pod"}, pod); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get Pod resource: %w", err)
+	}
+	// A replaced pod has to be gone before the server gets a new one
+	if pod.DeletionTimestamp != nil && !controllerutil.ContainsFinalizer(pod, SERVER_FINALIZER) {
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
 	// Ensure pod has the finalizers
 	update, err := r.ensurePodFinalizer(ctx, server)
 	if err != nil || update {
 		return ctrl.Result{}, err
 	}
 
-	if err := r.Status().Update(ctx, server); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to update Server resource: %w", err)
+	// Crashed containers are handled by the restart policy of the server
+	handled, err := r.handleCrash(ctx, server, pod, previousState)
+	if err != nil || handled {
+		return ctrl.Result{}, err
+	}
+
+	// Keep the lifecycle state in sync with the pod
+	// An allocated server keeps its state, unless the pod fails
+	state := utils.GetServerStateForPod(pod)
+	if previousState != gameserverv1alpha1.ServerStateAllocated || state == gameserverv1alpha1.ServerStateFailed {
+		server.Status.State = state
+	}
+	// A ready pod can still run a hung game, which only the heartbeats reveal
+	handled, err = r.handleHeartbeat(ctx, server, pod, previousState)
+	if err != nil || handled {
+		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+	if err := r.updateState(ctx, server, previousState); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Only running games can report players, heartbeats and if they allow deletion.
+	// In poll mode they have to be polled from the sidecar, in push mode the sidecar writes them to the pod, whose changes trigger a reconcile.
+	sync := ctrl.Result{RequeueAfter: PLAYER_SYNC_INTERVAL}
+	if utils.IsPushMode(server) {
+		sync = ctrl.Result{}
+	}
+	switch server.Status.State {
+	case gameserverv1alpha1.ServerStateReady, gameserverv1alpha1.ServerStateAllocated:
+	case gameserverv1alpha1.ServerStateUnhealthy:
+		return sync, nil
+	default:
+		return ctrl.Result{}, nil
+	}
+	if r.PlayerCounter != nil {
+		if err := r.updatePlayers(ctx, server, pod); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.updateDeleteAllowed(ctx, server, pod); err != nil {
+		return ctrl.Result{}, err
+	}
+	return sync, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -137,8 +208,18 @@ func (r *ServerReconciler) ensurePodExists(ctx context.Context, server *gameserv
 	}
 
 	if err != nil { // Pod does not exist
+		// A replaced pod is created again after a backoff, so a game that crashes on startup is not replaced in a hot loop
+		if getReplaceWait(server, time.Now()) > 0 {
+			return false, nil
+		}
 		newPod := utils.GetNewPod(server, server.Namespace)
-		r.emitEventf(server, corev1.EventTypeNormal, utils.ReasonServerInitialized, "Setting up sidecar with image %s", server.Spec.SidecarSettings.SidecarImage)
+		if utils.IsPushMode(server) && newPod.Spec.ServiceAccountName == utils.GetSidecarServiceAccountName(server) {
+			if err := r.ensureSidecarAccess(ctx, server); err != nil {
+				r.emitEventf(server, corev1.EventTypeWarning, utils.ReasonServerPodCreationFailed, "Failed to set up the sidecar service account: %s", err)
+				return false, err
+			}
+		}
+		r.emitEventf(server, corev1.EventTypeNormal, utils.ReasonServerInitialized, "Setting up sidecar with image %s", *utils.GetSidecarSettings(server).SidecarImage)
 		err = controllerutil.SetControllerReference(server, newPod, r.Scheme)
 		if err != nil {
 			r.emitEventf(server, corev1.EventTypeWarning, utils.ReasonServerInitialized, "failed to set pod owner reference: %s", err)
@@ -163,12 +244,28 @@ func (r *ServerReconciler) ensurePodExists(ctx context.Context, server *gameserv
 			Reason:             "PodCreatedSuccessfully",
 			Message:            "Pod has been successfully created",
 		})
+		server.Status.State = gameserverv1alpha1.ServerStateCreating
 		r.emitEvent(server, corev1.EventTypeNormal, utils.ReasonServerInitialized, "Pod created successfully")
 		return false, nil
 	}
 	return true, nil
 }
 
+// ensureSidecarAccess creates the service account, role and role binding that let the sidecar of the server push its state
+// They are owned by the server, so they are removed together with it
+func (r *ServerReconciler) ensureSidecarAccess(ctx context.Context, server *gameserverv1alpha1.Server) error {
+	serviceAccount, role, roleBinding := utils.GetSidecarAccess(server)
+	for _, object := range []client.Object{serviceAccount, role, roleBinding} {
+		if err := controllerutil.SetControllerReference(server, object, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set the owner of the sidecar access: %w", err)
+		}
+		if err := r.Create(ctx, object); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create the sidecar access: %w", err)
+		}
+	}
+	return nil
+}
+
 // handleDeletion handles the deletion process of the Server, by checking with the sidecar if it is allowed to be deleted
 func (r *ServerReconciler) handleDeletion(ctx context.Context, server *gameserverv1alpha1.Server) error {
 	pod := &corev1.Pod{}
@@ -176,17 +273,35 @@ func (r *ServerReconciler) handleDeletion(ctx context.Context, server *gameserve
 	if err := r.Get(ctx, namespacedName, pod); err != nil {
 		return err
 	}
-	allowed, err := r.DeletionAllowed.IsDeletionAllowed(server, pod)
+	previousState := server.Status.State
+	if !previousState.IsDeleting() {
+		server.Status.State = gameserverv1alpha1.ServerStateShutdownRequested
+	}
+	// A failed server has no game left to drain
+	allowed, err := true, error(nil)
+	if previousState != gameserverv1alpha1.ServerStateFailed {
+		allowed, err = r.DeletionAllowed.IsDeletionAllowed(server, pod)
+	}
 	if err != nil {
 		r.emitEvent(pod, corev1.EventTypeWarning, utils.ReasonServerDeletionNotAllowed, "Deletion request did not succeed")
 		r.emitEvent(server, corev1.EventTypeWarning, utils.ReasonServerDeletionNotAllowed, "Deletion request did not succeed")
+		if err := r.updateState(ctx, server, previousState); err != nil {
+			return err
+		}
 		return fmt.Errorf("failed to check for deletion for server: %s", err)
 	}
 	if !allowed {
 		r.emitEvent(pod, corev1.EventTypeNormal, utils.ReasonServerDeletionAllowed, "Server did not respond with allowed")
 		r.emitEvent(server, corev1.EventTypeNormal, utils.ReasonServerDeletionAllowed, "Server did not respond with allowed")
+		if err := r.updateState(ctx, server, previousState); err != nil {
+			return err
+		}
 		return errors.New("server deletion not allowed")
 	}
+	server.Status.State = gameserverv1alpha1.ServerStateDeleteAllowed
+	if err := r.updateState(ctx, server, previousState); err != nil {
+		return err
+	}
 
 	if pod != nil && controllerutil.ContainsFinalizer(pod, SERVER_FINALIZER) {
 		controllerutil.RemoveFinalizer(pod, SERVER_FINALIZER)
@@ -212,6 +327,194 @@ func (r *ServerReconciler) handleDeletion(ctx context.Context, server *gameserve
 		Message:            "Pod successfully deleted during finalization",
 	})
 	r.emitEvent(server, corev1.EventTypeNormal, utils.ReasonServerPodDeleted, "Pod successfully deleted during finalization")
+	server.Status.State = gameserverv1alpha1.ServerStateTerminating
+	return r.updateState(ctx, server, gameserverv1alpha1.ServerStateDeleteAllowed)
+}
+
+// handleCrash enforces the restart policy of the server when a container of its pod crashed.
+// Containers restarted in place by the kubelet are only counted.
+// It returns true when the pod was replaced or the server failed, so the state is no longer taken from the pod.
+func (r *ServerReconciler) handleCrash(ctx context.Context, server *gameserverv1alpha1.Server, pod *corev1.Pod, previousState gameserverv1alpha1.ServerState) (bool, error) {
+	policy := server.Spec.RestartPolicy
+	if policy != gameserverv1alpha1.ServerRestartReplace && policy != gameserverv1alpha1.ServerRestartFail {
+		return false, r.updateRestarts(ctx, server, pod)
+	}
+	// A failed server stays failed until its fleet replaces it
+	if previousState == gameserverv1alpha1.ServerStateFailed {
+		return true, nil
+	}
+	crash, crashed := utils.GetPodCrash(pod)
+	if !crashed {
+		return false, nil
+	}
+	return true, r.recoverServer(ctx, server, pod, previousState, utils.ReasonServerCrashed, crash)
+}
+
+// handleHeartbeat asks the sidecar, or reads what it pushed, if the game of a running server sent a heartbeat within its deadline.
+// A missed deadline marks a ready server as unhealthy, or recovers it with the Replace and Fail restart policies.
+// It returns true when the pod was replaced or the server failed, so the state is no longer taken from the pod.
+func (r *ServerReconciler) handleHeartbeat(ctx context.Context, server *gameserverv1alpha1.Server, pod *corev1.Pod, previousState gameserverv1alpha1.ServerState) (bool, error) {
+	if !r.watchesHeartbeat(server) {
+		return false, nil
+	}
+	if server.Status.State != gameserverv1alpha1.ServerStateReady && server.Status.State != gameserverv1alpha1.ServerStateAllocated {
+		return false, nil
+	}
+	healthy, err := r.HeartbeatChecker.IsHeartbeatHealthy(server, pod)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get the heartbeat from the sidecar", "server", server.Name)
+		return false, nil
+	}
+	if healthy {
+		return false, nil
+	}
+
+	cause := fmt.Sprintf("game missed its heartbeat deadline of %s", server.Spec.SidecarSettings.HeartbeatDeadline.Duration)
+	switch server.Spec.RestartPolicy {
+	case gameserverv1alpha1.ServerRestartReplace, gameserverv1alpha1.ServerRestartFail:
+		return true, r.recoverServer(ctx, server, pod, previousState, utils.ReasonServerUnhealthy, cause)
+	}
+	// An allocated server is in use, so it keeps its state
+	if server.Status.State == gameserverv1alpha1.ServerStateAllocated {
+		r.emitEventf(server, corev1.EventTypeWarning, utils.ReasonServerUnhealthy, "The %s", cause)
+		return false, nil
+	}
+	if previousState != gameserverv1alpha1.ServerStateUnhealthy {
+		r.emitEventf(server, corev1.EventTypeWarning, utils.ReasonServerUnhealthy, "The %s, marking the server as unhealthy", cause)
+	}
+	server.Status.State = gameserverv1alpha1.ServerStateUnhealthy
+	return false, nil
+}
+
+// watchesHeartbeat returns true if the game of the server has to send heartbeats to its sidecar
+func (r *ServerReconciler) watchesHeartbeat(server *gameserverv1alpha1.Server) bool {
+	return r.HeartbeatChecker != nil && server.Spec.SidecarSettings != nil && server.Spec.SidecarSettings.HeartbeatDeadline != nil
+}
+
+// recoverServer enforces the Replace or Fail restart policy of the server, after the cause made its pod unusable
+func (r *ServerReconciler) recoverServer(ctx context.Context, server *gameserverv1alpha1.Server, pod *corev1.Pod, previousState gameserverv1alpha1.ServerState,
+	reason utils.EventReason, cause string) error {
+	if server.Spec.RestartPolicy == gameserverv1alpha1.ServerRestartFail {
+		r.emitEventf(server, corev1.EventTypeWarning, reason, "The %s, marking the server as failed", cause)
+		server.Status.State = gameserverv1alpha1.ServerStateFailed
+		return r.updateState(ctx, server, previousState)
+	}
+
+	r.emitEventf(server, corev1.EventTypeWarning, reason, "The %s, replacing the pod", cause)
+	if controllerutil.ContainsFinalizer(pod, SERVER_FINALIZER) {
+		controllerutil.RemoveFinalizer(pod, SERVER_FINALIZER)
+		if err := r.Update(ctx, pod); err != nil {
+			return fmt.Errorf("failed to remove finalizer from the replaced pod: %w", err)
+		}
+	}
+	if err := r.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete the replaced pod: %w", err)
+	}
+	server.Status.Replacements++
+	server.Status.LastReplacementTime = &metav1.Time{Time: time.Now()}
+	server.Status.Restarts = 0
+	server.Status.State = gameserverv1alpha1.ServerStateCreating
+	if previousState == gameserverv1alpha1.ServerStateCreating {
+		if err := r.Status().Update(ctx, server); err != nil {
+			return fmt.Errorf("failed to update Server replacements: %w", err)
+		}
+		return nil
+	}
+	return r.updateState(ctx, server, previousState)
+}
+
+// getReplaceWait returns how long the replaced pod of the server still has to wait before it is created again
+func getReplaceWait(server *gameserverv1alpha1.Server, now time.Time) time.Duration {
+	if server.Status.LastReplacementTime == nil || server.Status.Replacements <= 0 {
+		return 0
+	}
+	backoff := MAX_REPLACE_BACKOFF
+	if shift := server.Status.Replacements - 1; shift < 8 {
+		backoff = min(REPLACE_BACKOFF<<shift, MAX_REPLACE_BACKOFF)
+	}
+	return max(0, server.Status.LastReplacementTime.Add(backoff).Sub(now))
+}
+
+// updateRestarts persists how often the kubelet restarted the containers of the pod in place
+func (r *ServerReconciler) updateRestarts(ctx context.Context, server *gameserverv1alpha1.Server, pod *corev1.Pod) error {
+	restarts := utils.GetRestartCount(pod)
+	if server.Status.Restarts == restarts {
+		return nil
+	}
+	if restarts > server.Status.Restarts {
+		r.emitEventf(server, corev1.EventTypeWarning, utils.ReasonServerCrashed, "A container was restarted in place, %d restarts so far", restarts)
+	}
+	server.Status.Restarts = restarts
+	if err := r.Status().Update(ctx, server); err != nil {
+		return fmt.Errorf("failed to update Server restarts: %w", err)
+	}
+	return nil
+}
+
+// updateState persists the status of the server if its state moved on from the previous state, and emits an event for the transition
+func (r *ServerReconciler) updateState(ctx context.Context, server *gameserverv1alpha1.Server, previous gameserverv1alpha1.ServerState) error {
+	current := server.Status.State
+	if current == previous {
+		return nil
+	}
+	if err := r.Status().Update(ctx, server); err != nil {
+		r.emitEventf(server, corev1.EventTypeWarning, utils.ReasonServerUpdateFAiled, "failed to update server state to %s: %s", current, err)
+		return fmt.Errorf("failed to update Server state: %w", err)
+	}
+	eventType := corev1.EventTypeNormal
+	if current == gameserverv1alpha1.ServerStateFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	if previous == "" {
+		r.emitEventf(server, eventType, utils.ReasonServerStateChanged, "Server state set to %s", current)
+		return nil
+	}
+	r.emitEventf(server, eventType, utils.ReasonServerStateChanged, "Server state changed from %s to %s", previous, current)
+	return nil
+}
+
+// updatePlayers reads the player count from the sidecar and persists it, if it changed.
+// Failing to reach the sidecar is only logged, as the count is refreshed on the next sync.
+func (r *ServerReconciler) updatePlayers(ctx context.Context, server *gameserverv1alpha1.Server, pod *corev1.Pod) error {
+	players, err := r.PlayerCounter.GetPlayerCount(server, pod)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get the player count from the sidecar", "server", server.Name)
+		return nil
+	}
+	if server.Status.Players == int32(players) {
+		return nil
+	}
+	server.Status.Players = int32(players)
+	if err := r.Status().Update(ctx, server); err != nil {
+		return fmt.Errorf("failed to update Server players: %w", err)
+	}
+	return nil
+}
+
+// updateDeleteAllowed reads if the game allowed the deletion of the server and persists it, if it changed.
+// An allocated server is released once its game allows the deletion, so it can be scaled down.
+// Failing to reach the sidecar is only logged, as it is asked again on the next sync.
+func (r *ServerReconciler) updateDeleteAllowed(ctx context.Context, server *gameserverv1alpha1.Server, pod *corev1.Pod) error {
+	allowed, err := r.DeletionAllowed.IsGameDeleteAllowed(server, pod)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get if the game allowed deletion from the sidecar", "server", server.Name)
+		return nil
+	}
+	if server.Status.DeleteAllowed == allowed {
+		return nil
+	}
+	previousState := server.Status.State
+	server.Status.DeleteAllowed = allowed
+	if allowed && previousState == gameserverv1alpha1.ServerStateAllocated {
+		server.Status.State = utils.GetServerStateForPod(pod)
+		server.Status.Allocation = ""
+	}
+	if err := r.Status().Update(ctx, server); err != nil {
+		return fmt.Errorf("failed to update Server delete allowed: %w", err)
+	}
+	if server.Status.State != previousState {
+		r.emitEvent(server, corev1.EventTypeNormal, utils.ReasonServerStateChanged, "Server released, the game allowed its deletion")
+	}
 	return nil
 }
 
@@ -18,7 +18,9 @@ package controller
 
 import (
 	"context"
+	"github.com/MirrorStudios/fallernetes/internal/utils"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"strings"
 	"time"
@@ -53,11 +55,31 @@ type TestChecker struct {
 	deleteAllowed map[string]bool
 }
 
+type TestPlayerCounter struct {
+	players int
+}
+
+func (p TestPlayerCounter) GetPlayerCount(server *gameserverv1alpha1.Server, pod *corev1.Pod) (int, error) {
+	return p.players, nil
+}
+
+type TestHeartbeatChecker struct {
+	healthy map[string]bool
+}
+
+func (h TestHeartbeatChecker) IsHeartbeatHealthy(server *gameserverv1alpha1.Server, pod *corev1.Pod) (bool, error) {
+	return h.healthy[server.Name], nil
+}
+
 func (p TestChecker) IsDeletionAllowed(server *gameserverv1alpha1.Server, pod *corev1.Pod) (bool, error) {
 	//Basically for mocking deletion allowing behaviour, we just use a map
 	return p.deleteAllowed[server.Name], nil
 }
 
+func (p TestChecker) IsGameDeleteAllowed(server *gameserverv1alpha1.Server, pod *corev1.Pod) (bool, error) {
+	return p.deleteAllowed[server.Name], nil
+}
+
 var _ = Describe("ServerReconciler", func() {
 	Context("Reconcile logic", func() {
 		const (
@@ -333,6 +355,420 @@ var _ = Describe("ServerReconciler", func() {
 			Expect(hasGlobalFinalizerRemoved).To(BeTrue())
 		})
 
+		It("should move the Server through its lifecycle states", func() {
+			recorder := NewFakeRecorder()
+			checker := TestChecker{
+				deleteAllowed: make(map[string]bool),
+			}
+			reconciler := &ServerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				ErrorOnNotAllowed: true,
+				DeletionAllowed:   checker,
+				Recorder:          recorder,
+			}
+			hasEvent := func(message string) bool {
+				for _, event := range recorder.Events {
+					if event.Message == message {
+						return true
+					}
+				}
+				return false
+			}
+
+			By("Reconciling until the pod is created")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateCreating))
+			Expect(hasEvent("Server state set to Creating")).To(BeTrue())
+
+			By("Reconciling while the pod is not running")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateStarting))
+
+			By("Marking the pod as running and ready")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ServerName + "-pod", Namespace: ServerNamespace}, pod)).To(Succeed())
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+			Expect(hasEvent("Server state changed from Starting to Ready")).To(BeTrue())
+
+			By("Deleting the server while deletion is not allowed")
+			Expect(k8sClient.Delete(ctx, server)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).To(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateShutdownRequested))
+
+			By("Allowing the deletion")
+			checker.deleteAllowed[server.Name] = true
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasEvent("Server state changed from ShutdownRequested to DeleteAllowed")).To(BeTrue())
+			Expect(hasEvent("Server state changed from DeleteAllowed to Terminating")).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, namespacedName, server))).To(BeTrue())
+		})
+
+		It("should mirror the player count of a ready server", func() {
+			reconciler := &ServerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				ErrorOnNotAllowed: true,
+				DeletionAllowed:   TestChecker{deleteAllowed: make(map[string]bool)},
+				PlayerCounter:     TestPlayerCounter{players: 6},
+				Recorder:          NewFakeRecorder(),
+			}
+
+			By("Reconciling until the pod exists")
+			for range 3 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.Players).To(BeZero())
+
+			By("Marking the pod as running and ready")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ServerName + "-pod", Namespace: ServerNamespace}, pod)).To(Succeed())
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(PLAYER_SYNC_INTERVAL))
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+			Expect(server.Status.Players).To(Equal(int32(6)))
+		})
+
+		It("should release an allocated server once its game allows deletion", func() {
+			checker := TestChecker{deleteAllowed: make(map[string]bool)}
+			reconciler := &ServerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				ErrorOnNotAllowed: true,
+				DeletionAllowed:   checker,
+				Recorder:          NewFakeRecorder(),
+			}
+
+			By("Reconciling until the pod exists")
+			for range 3 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ServerName + "-pod", Namespace: ServerNamespace}, pod)).To(Succeed())
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("Allocating the server")
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			server.Status.State = gameserverv1alpha1.ServerStateAllocated
+			server.Status.Allocation = "allocation"
+			Expect(k8sClient.Status().Update(ctx, server)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateAllocated))
+			Expect(server.Status.DeleteAllowed).To(BeFalse())
+
+			By("Allowing the deletion in the game")
+			checker.deleteAllowed[ServerName] = true
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(PLAYER_SYNC_INTERVAL))
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+			Expect(server.Status.DeleteAllowed).To(BeTrue())
+			Expect(server.Status.Allocation).To(BeEmpty())
+		})
+
+		It("should mark servers that miss their heartbeat as unhealthy", func() {
+			recorder := NewFakeRecorder()
+			heartbeats := TestHeartbeatChecker{healthy: map[string]bool{ServerName: true}}
+			reconciler := &ServerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				ErrorOnNotAllowed: true,
+				DeletionAllowed:   TestChecker{deleteAllowed: make(map[string]bool)},
+				HeartbeatChecker:  heartbeats,
+				Recorder:          recorder,
+			}
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			port := 8080
+			image := "sidecar"
+			server.Spec.SidecarSettings = &gameserverv1alpha1.SidecarSettings{
+				Port:              &port,
+				SidecarImage:      &image,
+				HeartbeatDeadline: &metav1.Duration{Duration: 30 * time.Second},
+			}
+			Expect(k8sClient.Update(ctx, server)).To(Succeed())
+
+			By("Reconciling until the pod is ready")
+			for range 3 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			podName := types.NamespacedName{Name: ServerName + "-pod", Namespace: ServerNamespace}
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, podName, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[1].Env).To(ContainElement(corev1.EnvVar{Name: "HEARTBEAT_DEADLINE", Value: "30s"}))
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(PLAYER_SYNC_INTERVAL))
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+
+			By("Missing the heartbeat deadline")
+			heartbeats.healthy[ServerName] = false
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(PLAYER_SYNC_INTERVAL))
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateUnhealthy))
+			hasUnhealthyEvent := false
+			for _, event := range recorder.Events {
+				if event.Message == "The game missed its heartbeat deadline of 30s, marking the server as unhealthy" {
+					hasUnhealthyEvent = true
+					break
+				}
+			}
+			Expect(hasUnhealthyEvent).To(BeTrue())
+
+			By("Recovering once the game sends heartbeats again")
+			heartbeats.healthy[ServerName] = true
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+
+			By("Replacing the pod with the Replace restart policy")
+			server.Spec.RestartPolicy = gameserverv1alpha1.ServerRestartReplace
+			Expect(k8sClient.Update(ctx, server)).To(Succeed())
+			heartbeats.healthy[ServerName] = false
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateCreating))
+			Expect(server.Status.Replacements).To(BeEquivalentTo(1))
+			Expect(server.Status.LastReplacementTime).ToNot(BeNil())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, podName, &corev1.Pod{}))
+			}, time.Second*5, time.Millisecond*100).Should(BeTrue())
+
+			// Skip the backoff, so the pod is created again before the server is cleaned up
+			server.Status.LastReplacementTime = &metav1.Time{Time: time.Now().Add(-REPLACE_BACKOFF)}
+			Expect(k8sClient.Status().Update(ctx, server)).To(Succeed())
+		})
+
+		It("should set up the service account of the sidecar in push mode", func() {
+			reconciler := &ServerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				ErrorOnNotAllowed: true,
+				DeletionAllowed:   TestChecker{deleteAllowed: make(map[string]bool)},
+				Recorder:          NewFakeRecorder(),
+			}
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			port := 8080
+			image := "sidecar"
+			server.Spec.SidecarSettings = &gameserverv1alpha1.SidecarSettings{
+				Port:              &port,
+				SidecarImage:      &image,
+				Mode:              gameserverv1alpha1.SidecarModePush,
+				HeartbeatDeadline: &metav1.Duration{Duration: time.Minute},
+			}
+			Expect(k8sClient.Update(ctx, server)).To(Succeed())
+
+			for range 3 {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ServerName + "-pod", Namespace: ServerNamespace}, pod)).To(Succeed())
+			Expect(pod.Spec.ServiceAccountName).To(Equal(ServerName + "-sidecar"))
+			Expect(*pod.Spec.AutomountServiceAccountToken).To(BeFalse())
+			accessName := types.NamespacedName{Name: ServerName + "-sidecar", Namespace: ServerNamespace}
+			serviceAccount := &corev1.ServiceAccount{}
+			Expect(k8sClient.Get(ctx, accessName, serviceAccount)).To(Succeed())
+			Expect(metav1.IsControlledBy(serviceAccount, server)).To(BeTrue())
+			role := &rbacv1.Role{}
+			Expect(k8sClient.Get(ctx, accessName, role)).To(Succeed())
+			Expect(role.Rules[0].ResourceNames).To(Equal([]string{ServerName + "-pod"}))
+			Expect(metav1.IsControlledBy(role, server)).To(BeTrue())
+			roleBinding := &rbacv1.RoleBinding{}
+			Expect(k8sClient.Get(ctx, accessName, roleBinding)).To(Succeed())
+			Expect(metav1.IsControlledBy(roleBinding, server)).To(BeTrue())
+			Expect(roleBinding.Subjects).To(HaveLen(1))
+			Expect(roleBinding.Subjects[0].Name).To(Equal(serviceAccount.Name))
+
+			By("Reading the pushed state instead of polling the sidecar")
+			reconciler.PlayerCounter = utils.ProdPlayerCounter{}
+			reconciler.DeletionAllowed = utils.ProdDeletionChecker{}
+			reconciler.HeartbeatChecker = utils.ProdHeartbeatChecker{}
+			pod.Annotations = map[string]string{
+				gameserverv1alpha1.PlayersAnnotation:       "5",
+				gameserverv1alpha1.DeleteAllowedAnnotation: "true",
+			}
+			Expect(k8sClient.Update(ctx, pod)).To(Succeed())
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.Players).To(BeEquivalentTo(5))
+			Expect(server.Status.DeleteAllowed).To(BeTrue())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+
+			By("Reading the pushed heartbeat instead of polling the sidecar")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ServerName + "-pod", Namespace: ServerNamespace}, pod)).To(Succeed())
+			pod.Annotations[gameserverv1alpha1.HeartbeatHealthyAnnotation] = "false"
+			Expect(k8sClient.Update(ctx, pod)).To(Succeed())
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateUnhealthy))
+		})
+
+		It("should enforce the restart policy of the server", func() {
+			recorder := NewFakeRecorder()
+			reconciler := &ServerReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				ErrorOnNotAllowed: true,
+				DeletionAllowed:   TestChecker{deleteAllowed: make(map[string]bool)},
+				Recorder:          recorder,
+			}
+			podName := types.NamespacedName{Name: ServerName + "-pod", Namespace: ServerNamespace}
+			reconcileUntilPodExists := func() {
+				for range 3 {
+					_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+			setContainerStatus := func(status corev1.ContainerStatus) {
+				pod := &corev1.Pod{}
+				Expect(k8sClient.Get(ctx, podName, pod)).To(Succeed())
+				status.Name = "nginx"
+				status.Image = "nginx:1.7.9"
+				pod.Status.Phase = corev1.PodRunning
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{status}
+				Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+			}
+			setRestartPolicy := func(policy gameserverv1alpha1.ServerRestartPolicy) {
+				server := &gameserverv1alpha1.Server{}
+				Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+				server.Spec.RestartPolicy = policy
+				Expect(k8sClient.Update(ctx, server)).To(Succeed())
+			}
+			server := &gameserverv1alpha1.Server{}
+
+			By("Counting the restarts in place")
+			reconcileUntilPodExists()
+			setContainerStatus(corev1.ContainerStatus{
+				RestartCount: 2,
+				State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			})
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.Restarts).To(BeEquivalentTo(2))
+
+			By("Replacing the pod after a crash")
+			setRestartPolicy(gameserverv1alpha1.ServerRestartReplace)
+			setContainerStatus(corev1.ContainerStatus{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			})
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.Replacements).To(BeEquivalentTo(1))
+			Expect(server.Status.Restarts).To(BeZero())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateCreating))
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, podName, &corev1.Pod{}))
+			}, time.Second*5, time.Millisecond*100).Should(BeTrue())
+
+			By("Waiting for the backoff before creating the pod again")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", REPLACE_BACKOFF))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, podName, &corev1.Pod{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			server.Status.LastReplacementTime = &metav1.Time{Time: time.Now().Add(-REPLACE_BACKOFF)}
+			Expect(k8sClient.Status().Update(ctx, server)).To(Succeed())
+			reconcileUntilPodExists()
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, podName, pod)).To(Succeed())
+			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+
+			By("Failing the server after a crash")
+			setRestartPolicy(gameserverv1alpha1.ServerRestartFail)
+			setContainerStatus(corev1.ContainerStatus{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137}},
+			})
+			for range 2 {
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(k8sClient.Get(ctx, namespacedName, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateFailed))
+			hasCrashEvent := false
+			for _, event := range recorder.Events {
+				if event.Message == "The container nginx exited with code 137, marking the server as failed" {
+					hasCrashEvent = true
+					break
+				}
+			}
+			Expect(hasCrashEvent).To(BeTrue())
+
+			By("Deleting the failed server without asking the sidecar")
+			Expect(k8sClient.Delete(ctx, server)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, namespacedName, server))).To(BeTrue())
+		})
+
+		It("should back off exponentially when replacing pods", func() {
+			now := time.Now()
+			server := &gameserverv1alpha1.Server{}
+			Expect(getReplaceWait(server, now)).To(BeZero())
+			server.Status.LastReplacementTime = &metav1.Time{Time: now}
+			server.Status.Replacements = 1
+			Expect(getReplaceWait(server, now)).To(Equal(REPLACE_BACKOFF))
+			server.Status.Replacements = 3
+			Expect(getReplaceWait(server, now)).To(Equal(4 * REPLACE_BACKOFF))
+			Expect(getReplaceWait(server, now.Add(time.Minute))).To(BeZero())
+			server.Status.Replacements = 100
+			Expect(getReplaceWait(server, now)).To(Equal(MAX_REPLACE_BACKOFF))
+		})
+
 		It("Should return error on get fail", func() {
 			checker := TestChecker{
 				deleteAllowed: make(map[string]bool),
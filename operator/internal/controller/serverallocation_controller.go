@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/MirrorStudios/fallernetes/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	gameserverv1alpha1 "github.com/MirrorStudios/fallernetes/api/v1alpha1"
+)
+
+// ALLOCATION_FINALIZER keeps an allocation around until the server it claimed is released
+const ALLOCATION_FINALIZER = "serverallocations.falloria.com/finalizer"
+
+// ServerAllocationReconciler reconciles a ServerAllocation object
+type ServerAllocationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=gameserver.falloria.com,resources=serverallocations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gameserver.falloria.com,resources=serverallocations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=gameserver.falloria.com,resources=serverallocations/finalizers,verbs=update
+// +kubebuilder:rbac:groups=gameserver.falloria.com,resources=servers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=gameserver.falloria.com,resources=servers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// An allocation is only handled once, after that its status is final.
+// The chosen server is recorded on the allocation before it is claimed, so a retry claims the same server instead of another one.
+// Deleting the allocation releases its server.
+func (r *ServerAllocationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("allocation", req.Name, "namespace", req.Namespace)
+
+	allocation := &gameserverv1alpha1.ServerAllocation{}
+	if err := r.Get(ctx, req.NamespacedName, allocation); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get ServerAllocation: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if allocation.DeletionTimestamp != nil {
+		return ctrl.Result{}, r.handleDeletion(ctx, allocation)
+	}
+
+	if allocation.Status.State != "" {
+		return ctrl.Result{}, nil
+	}
+
+	if allocation.Status.ServerName == "" {
+		selector, err := utils.GetAllocationSelector(allocation)
+		if err != nil {
+			allocation.Status.State = gameserverv1alpha1.AllocationStateInvalid
+			allocation.Status.Message = err.Error()
+			r.emitEventf(allocation, corev1.EventTypeWarning, utils.ReasonServerAllocationInvalid, "Invalid allocation: %s", err)
+			return ctrl.Result{}, r.updateStatus(ctx, allocation)
+		}
+
+		servers := &gameserverv1alpha1.ServerList{}
+		if err := r.List(ctx, servers, client.InNamespace(allocation.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list servers: %w", err)
+		}
+		allocatable := utils.GetAllocatableServers(servers)
+		if len(allocatable) == 0 {
+			allocation.Status.State = gameserverv1alpha1.AllocationStateUnAllocated
+			allocation.Status.Message = "no ready server matched the allocation"
+			r.emitEvent(allocation, corev1.EventTypeWarning, utils.ReasonServerAllocationUnAllocated, "No ready server available")
+			return ctrl.Result{}, r.updateStatus(ctx, allocation)
+		}
+
+		if !controllerutil.ContainsFinalizer(allocation, ALLOCATION_FINALIZER) {
+			controllerutil.AddFinalizer(allocation, ALLOCATION_FINALIZER)
+			if err := r.Update(ctx, allocation); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to add finalizer to ServerAllocation: %w", err)
+			}
+		}
+		allocation.Status.ServerName = allocatable[0].Name
+		if err := r.updateStatus(ctx, allocation); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	server := &gameserverv1alpha1.Server{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: allocation.Namespace, Name: allocation.Status.ServerName}, server)
+	if client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get the allocated server: %w", err)
+	}
+	if err != nil || !utils.IsAllocatableBy(server, allocation) {
+		logger.Info("Server is no longer available, choosing another one", "server", allocation.Status.ServerName)
+		allocation.Status.ServerName = ""
+		return ctrl.Result{Requeue: true}, r.updateStatus(ctx, allocation)
+	}
+
+	if server.Status.State != gameserverv1alpha1.ServerStateAllocated {
+		// The update fails with a conflict if the server changed since it was read,
+		// which makes sure a server is never handed out twice
+		server.Status.State = gameserverv1alpha1.ServerStateAllocated
+		server.Status.Allocation = allocation.Name
+		if err := r.Status().Update(ctx, server); err != nil {
+			if errors.IsConflict(err) {
+				logger.Info("Server changed while allocating, trying again", "server", server.Name)
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("failed to allocate server: %w", err)
+		}
+		r.emitEventf(server, corev1.EventTypeNormal, utils.ReasonServerStateChanged, "Server allocated by %s", allocation.Name)
+	}
+
+	// The allocation is garbage collected together with its server
+	owned, err := controllerutil.HasOwnerReference(allocation.OwnerReferences, server, r.Scheme)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check the owner of ServerAllocation: %w", err)
+	}
+	if !owned {
+		if err := controllerutil.SetOwnerReference(server, allocation, r.Scheme); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to set the owner of ServerAllocation: %w", err)
+		}
+		if err := r.Update(ctx, allocation); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update ServerAllocation owner: %w", err)
+		}
+	}
+
+	allocation.Status.State = gameserverv1alpha1.AllocationStateAllocated
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: server.Namespace, Name: server.Name + "-pod"}, pod); err != nil {
+		logger.Error(err, "Failed to get the pod of the allocated server", "server", server.Name)
+	} else {
+		allocation.Status.PodIP = pod.Status.PodIP
+		allocation.Status.NodeName = pod.Spec.NodeName
+	}
+	r.emitEventf(allocation, corev1.EventTypeNormal, utils.ReasonServerAllocationAllocated, "Allocated server %s", server.Name)
+	return ctrl.Result{}, r.updateStatus(ctx, allocation)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServerAllocationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gameserverv1alpha1.ServerAllocation{}).
+		Complete(r)
+}
+
+// handleDeletion releases the server of the deleted allocation, if the allocation still holds it, and removes the finalizer
+func (r *ServerAllocationReconciler) handleDeletion(ctx context.Context, allocation *gameserverv1alpha1.ServerAllocation) error {
+	if !controllerutil.ContainsFinalizer(allocation, ALLOCATION_FINALIZER) {
+		return nil
+	}
+	if allocation.Status.ServerName != "" {
+		server := &gameserverv1alpha1.Server{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: allocation.Namespace, Name: allocation.Status.ServerName}, server)
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get the allocated server: %w", err)
+		}
+		if err == nil && server.Status.State == gameserverv1alpha1.ServerStateAllocated && server.Status.Allocation == allocation.Name {
+			// The server controller moves the state on from ready, if the pod is no longer ready
+			server.Status.State = gameserverv1alpha1.ServerStateReady
+			server.Status.Allocation = ""
+			if err := r.Status().Update(ctx, server); err != nil {
+				return fmt.Errorf("failed to release server: %w", err)
+			}
+			r.emitEventf(server, corev1.EventTypeNormal, utils.ReasonServerStateChanged, "Server released by %s", allocation.Name)
+		}
+	}
+	controllerutil.RemoveFinalizer(allocation, ALLOCATION_FINALIZER)
+	if err := r.Update(ctx, allocation); err != nil {
+		return fmt.Errorf("failed to remove finalizer from ServerAllocation: %w", err)
+	}
+	return nil
+}
+
+// updateStatus is used by the ServerAllocationReconciler to persist the result of an allocation
+func (r *ServerAllocationReconciler) updateStatus(ctx context.Context, allocation *gameserverv1alpha1.ServerAllocation) error {
+	if err := r.Status().Update(ctx, allocation); err != nil {
+		return fmt.Errorf("failed to update ServerAllocation status: %w", err)
+	}
+	return nil
+}
+
+// emitEvent is used to quickly emit events from the ServerAllocationReconciler
+func (r *ServerAllocationReconciler) emitEvent(object runtime.Object, eventtype string, reason utils.EventReason, message string) {
+	r.Recorder.Event(object, eventtype, string(reason), message)
+}
+
+// emitEventf is used to quickly emit events from the ServerAllocationReconciler with arguments
+func (r *ServerAllocationReconciler) emitEventf(object runtime.Object, eventtype string, reason utils.EventReason, message string, args ...interface{}) {
+	r.Recorder.Eventf(object, eventtype, string(reason), message, args...)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gameserverv1alpha1 "github.com/MirrorStudios/fallernetes/api/v1alpha1"
+)
+
+const allocationFleetName = "allocation-fleet"
+
+var _ = Describe("ServerAllocation Controller", func() {
+	Context("When reconciling a resource", func() {
+		ctx := context.Background()
+		var reconciler *ServerAllocationReconciler
+
+		createServer := func(name string, state gameserverv1alpha1.ServerState, labels map[string]string) {
+			server := &gameserverv1alpha1.Server{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels:    labels,
+				},
+				Spec: basicServerSpec,
+			}
+			Expect(k8sClient.Create(ctx, server)).To(Succeed())
+			server.Status.State = state
+			Expect(k8sClient.Status().Update(ctx, server)).To(Succeed())
+		}
+
+		allocate := func(name string, spec gameserverv1alpha1.ServerAllocationSpec) *gameserverv1alpha1.ServerAllocation {
+			allocation := &gameserverv1alpha1.ServerAllocation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: spec,
+			}
+			Expect(k8sClient.Create(ctx, allocation)).To(Succeed())
+			namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, allocation)).To(Succeed())
+			return allocation
+		}
+
+		BeforeEach(func() {
+			reconciler = &ServerAllocationReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: NewFakeRecorder(),
+			}
+
+			By("creating servers for the fleet")
+			createServer("allocation-ready", gameserverv1alpha1.ServerStateReady, map[string]string{"fleet": allocationFleetName, "map": "dust"})
+			createServer("allocation-starting", gameserverv1alpha1.ServerStateStarting, map[string]string{"fleet": allocationFleetName, "map": "dust"})
+			createServer("allocation-other-map", gameserverv1alpha1.ServerStateReady, map[string]string{"fleet": allocationFleetName, "map": "nuke"})
+
+			By("creating the pod of the ready server")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "allocation-ready-pod",
+					Namespace: namespace,
+				},
+				Spec: corev1.PodSpec{
+					NodeName:   "node-1",
+					Containers: basicServerSpec.Pod.Containers,
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status.PodIP = "10.0.0.1"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.DeleteAllOf(ctx, &gameserverv1alpha1.ServerAllocation{}, client.InNamespace(namespace))).To(Succeed())
+			allocations := &gameserverv1alpha1.ServerAllocationList{}
+			Expect(k8sClient.List(ctx, allocations, client.InNamespace(namespace))).To(Succeed())
+			for _, allocation := range allocations.Items {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: allocation.Name, Namespace: namespace}})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(k8sClient.DeleteAllOf(ctx, &gameserverv1alpha1.Server{}, client.InNamespace(namespace), client.MatchingLabels{"fleet": allocationFleetName})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "allocation-ready-pod", Namespace: namespace}}, client.GracePeriodSeconds(0))).To(Succeed())
+		})
+
+		It("should allocate a ready server matching the selector only once", func() {
+			spec := gameserverv1alpha1.ServerAllocationSpec{
+				FleetName: allocationFleetName,
+				Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"map": "dust"}},
+			}
+
+			By("allocating the ready server")
+			allocation := allocate("allocation-first", spec)
+			Expect(allocation.Status.State).To(Equal(gameserverv1alpha1.AllocationStateAllocated))
+			Expect(allocation.Status.ServerName).To(Equal("allocation-ready"))
+			Expect(allocation.Status.PodIP).To(Equal("10.0.0.1"))
+			Expect(allocation.Status.NodeName).To(Equal("node-1"))
+
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "allocation-ready", Namespace: namespace}, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateAllocated))
+			Expect(server.Status.Allocation).To(Equal("allocation-first"))
+			Expect(allocation.Finalizers).To(ContainElement(ALLOCATION_FINALIZER))
+			Expect(metav1.IsControlledBy(allocation, server)).To(BeFalse())
+			Expect(allocation.OwnerReferences).To(ContainElement(HaveField("Name", "allocation-ready")))
+
+			By("not allocating the same server again")
+			allocation = allocate("allocation-second", spec)
+			Expect(allocation.Status.State).To(Equal(gameserverv1alpha1.AllocationStateUnAllocated))
+			Expect(allocation.Status.ServerName).To(BeEmpty())
+		})
+
+		It("should not change an allocation that was already handled", func() {
+			allocation := allocate("allocation-handled", gameserverv1alpha1.ServerAllocationSpec{FleetName: "missing-fleet"})
+			Expect(allocation.Status.State).To(Equal(gameserverv1alpha1.AllocationStateUnAllocated))
+
+			allocation.Spec.FleetName = allocationFleetName
+			Expect(k8sClient.Update(ctx, allocation)).To(Succeed())
+			namespacedName := types.NamespacedName{Name: allocation.Name, Namespace: namespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, allocation)).To(Succeed())
+			Expect(allocation.Status.State).To(Equal(gameserverv1alpha1.AllocationStateUnAllocated))
+		})
+
+		It("should release the server when the allocation is deleted", func() {
+			allocation := allocate("allocation-released", gameserverv1alpha1.ServerAllocationSpec{
+				FleetName: allocationFleetName,
+				Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"map": "dust"}},
+			})
+			Expect(allocation.Status.ServerName).To(Equal("allocation-ready"))
+
+			Expect(k8sClient.Delete(ctx, allocation)).To(Succeed())
+			namespacedName := types.NamespacedName{Name: allocation.Name, Namespace: namespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, namespacedName, allocation))).To(BeTrue())
+
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "allocation-ready", Namespace: namespace}, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+			Expect(server.Status.Allocation).To(BeEmpty())
+		})
+
+		It("should claim the recorded server again after a failed status update", func() {
+			By("recording the server and claiming it, without reporting the allocation")
+			allocation := &gameserverv1alpha1.ServerAllocation{
+				ObjectMeta: metav1.ObjectMeta{Name: "allocation-retried", Namespace: namespace},
+				Spec:       gameserverv1alpha1.ServerAllocationSpec{FleetName: allocationFleetName},
+			}
+			Expect(k8sClient.Create(ctx, allocation)).To(Succeed())
+			allocation.Status.ServerName = "allocation-other-map"
+			Expect(k8sClient.Status().Update(ctx, allocation)).To(Succeed())
+			server := &gameserverv1alpha1.Server{}
+			serverName := types.NamespacedName{Name: "allocation-other-map", Namespace: namespace}
+			Expect(k8sClient.Get(ctx, serverName, server)).To(Succeed())
+			server.Status.State = gameserverv1alpha1.ServerStateAllocated
+			server.Status.Allocation = allocation.Name
+			Expect(k8sClient.Status().Update(ctx, server)).To(Succeed())
+
+			By("retrying the allocation")
+			namespacedName := types.NamespacedName{Name: allocation.Name, Namespace: namespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, allocation)).To(Succeed())
+			Expect(allocation.Status.State).To(Equal(gameserverv1alpha1.AllocationStateAllocated))
+			Expect(allocation.Status.ServerName).To(Equal("allocation-other-map"))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "allocation-ready", Namespace: namespace}, server)).To(Succeed())
+			Expect(server.Status.State).To(Equal(gameserverv1alpha1.ServerStateReady))
+		})
+
+		It("should choose another server when the recorded one was taken", func() {
+			allocation := &gameserverv1alpha1.ServerAllocation{
+				ObjectMeta: metav1.ObjectMeta{Name: "allocation-taken", Namespace: namespace},
+				Spec:       gameserverv1alpha1.ServerAllocationSpec{FleetName: allocationFleetName},
+			}
+			Expect(k8sClient.Create(ctx, allocation)).To(Succeed())
+			allocation.Status.ServerName = "allocation-other-map"
+			Expect(k8sClient.Status().Update(ctx, allocation)).To(Succeed())
+			server := &gameserverv1alpha1.Server{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "allocation-other-map", Namespace: namespace}, server)).To(Succeed())
+			server.Status.State = gameserverv1alpha1.ServerStateAllocated
+			server.Status.Allocation = "someone-else"
+			Expect(k8sClient.Status().Update(ctx, server)).To(Succeed())
+
+			namespacedName := types.NamespacedName{Name: allocation.Name, Namespace: namespace}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(k8sClient.Get(ctx, namespacedName, allocation)).To(Succeed())
+			Expect(allocation.Status.ServerName).To(BeEmpty())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, namespacedName, allocation)).To(Succeed())
+			Expect(allocation.Status.State).To(Equal(gameserverv1alpha1.AllocationStateAllocated))
+			Expect(allocation.Status.ServerName).To(Equal("allocation-ready"))
+		})
+
+		It("should mark an allocation without a fleet or gametype as invalid", func() {
+			allocation := allocate("allocation-invalid", gameserverv1alpha1.ServerAllocationSpec{})
+			Expect(allocation.Status.State).To(Equal(gameserverv1alpha1.AllocationStateInvalid))
+			Expect(allocation.Status.Message).NotTo(BeEmpty())
+		})
+	})
+})
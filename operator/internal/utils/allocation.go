@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// GetAllocationSelector builds the label selector for the servers an allocation can claim.
+// It combines the fleet or gametype label with the additional selector of the allocation.
+func GetAllocationSelector(allocation *v1alpha1.ServerAllocation) (labels.Selector, error) {
+	spec := allocation.Spec
+	if (spec.FleetName == "") == (spec.GameTypeName == "") {
+		return nil, fmt.Errorf("exactly one of fleetName and gameTypeName has to be set")
+	}
+
+	selector := labels.Everything()
+	if spec.Selector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %w", err)
+		}
+	}
+
+	key, value := "fleet", spec.FleetName
+	if spec.GameTypeName != "" {
+		key, value = "gametype", spec.GameTypeName
+	}
+	requirement, err := labels.NewRequirement(key, selection.Equals, []string{value})
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s name: %w", key, err)
+	}
+	return selector.Add(*requirement), nil
+}
+
+// GetAllocatableServers returns the servers which are ready and not being deleted, oldest first
+func GetAllocatableServers(servers *v1alpha1.ServerList) []*v1alpha1.Server {
+	var allocatable []*v1alpha1.Server
+	for i := range servers.Items {
+		server := &servers.Items[i]
+		if !IsAllocatable(server) {
+			continue
+		}
+		allocatable = append(allocatable, server)
+	}
+	sort.SliceStable(allocatable, func(i, j int) bool {
+		return allocatable[i].CreationTimestamp.Before(&allocatable[j].CreationTimestamp)
+	})
+	return allocatable
+}
+
+// IsAllocatable returns true if the server is ready, not being deleted, and its game has not allowed the deletion
+func IsAllocatable(server *v1alpha1.Server) bool {
+	return server.DeletionTimestamp == nil && server.Status.State == v1alpha1.ServerStateReady && !server.Status.DeleteAllowed
+}
+
+// IsAllocatableBy returns true if the allocation can claim the server, or already claimed it
+func IsAllocatableBy(server *v1alpha1.Server, allocation *v1alpha1.ServerAllocation) bool {
+	if server.Status.State == v1alpha1.ServerStateAllocated {
+		return server.DeletionTimestamp == nil && server.Status.Allocation == allocation.Name
+	}
+	return IsAllocatable(server)
+}
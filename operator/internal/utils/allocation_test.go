@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ = Describe("Allocation Utility Testing", func() {
+	Context("When building the selector of an allocation", func() {
+		It("Should require exactly one of fleet and gametype", func() {
+			allocation := &v1alpha1.ServerAllocation{}
+			_, err := GetAllocationSelector(allocation)
+			Expect(err).To(HaveOccurred())
+
+			allocation.Spec.FleetName = "fleet"
+			allocation.Spec.GameTypeName = "gametype"
+			_, err = GetAllocationSelector(allocation)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should combine the fleet with the selector", func() {
+			allocation := &v1alpha1.ServerAllocation{Spec: v1alpha1.ServerAllocationSpec{
+				FleetName: "fleet",
+				Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"map": "dust"}},
+			}}
+			selector, err := GetAllocationSelector(allocation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"fleet": "fleet", "map": "dust"})).To(BeTrue())
+			Expect(selector.Matches(labels.Set{"fleet": "fleet", "map": "nuke"})).To(BeFalse())
+			Expect(selector.Matches(labels.Set{"fleet": "other", "map": "dust"})).To(BeFalse())
+		})
+
+		It("Should select on the gametype label", func() {
+			allocation := &v1alpha1.ServerAllocation{Spec: v1alpha1.ServerAllocationSpec{GameTypeName: "gametype"}}
+			selector, err := GetAllocationSelector(allocation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"gametype": "gametype", "fleet": "gametype-abc"})).To(BeTrue())
+			Expect(selector.Matches(labels.Set{"fleet": "gametype-abc"})).To(BeFalse())
+		})
+	})
+
+	Context("When getting the allocatable servers", func() {
+		It("Should only return ready servers whose game has not allowed deletion, oldest first", func() {
+			baseTime := time.Now()
+			deletionTime := metav1.Now()
+			servers := v1alpha1.ServerList{Items: []v1alpha1.Server{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "ready-new", CreationTimestamp: metav1.Time{Time: baseTime.Add(time.Hour)}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "allocated", CreationTimestamp: metav1.Time{Time: baseTime}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateAllocated},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "starting", CreationTimestamp: metav1.Time{Time: baseTime}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateStarting},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "deleting", CreationTimestamp: metav1.Time{Time: baseTime}, DeletionTimestamp: &deletionTime},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "delete-allowed", CreationTimestamp: metav1.Time{Time: baseTime}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady, DeleteAllowed: true},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "ready-old", CreationTimestamp: metav1.Time{Time: baseTime}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady},
+				},
+			}}
+			allocatable := GetAllocatableServers(&servers)
+			Expect(allocatable).To(HaveLen(2))
+			Expect(allocatable[0].Name).To(Equal("ready-old"))
+			Expect(allocatable[1].Name).To(Equal("ready-new"))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MAX_RECOMMENDATIONS limits how many recommendations are kept in the status, the oldest ones are dropped first
+const MAX_RECOMMENDATIONS = 50
+
+// StabilizeRecommendation records the recommendation in the status, and stabilizes it over the windows of the behavior.
+// Like the HorizontalPodAutoscaler, scaling up uses the lowest recommendation of the scale up window,
+// and scaling down uses the highest recommendation of the scale down window.
+// It returns the stabilized replicas, and which window changed the recommendation, which is empty when none did.
+func StabilizeRecommendation(behavior *v1alpha1.AutoscalerBehavior, status *v1alpha1.GameTypeAutoscalerStatus,
+	current int32, recommendation int32, now time.Time) (int32, string) {
+	var upWindow, downWindow time.Duration
+	if behavior != nil {
+		upWindow = getStabilizationWindow(behavior.ScaleUp)
+		downWindow = getStabilizationWindow(behavior.ScaleDown)
+	}
+	recordRecommendation(status, recommendation, now, max(upWindow, downWindow))
+
+	upRecommendation := recommendation
+	downRecommendation := recommendation
+	for _, previous := range status.Recommendations {
+		age := now.Sub(previous.Time.Time)
+		if age <= upWindow {
+			upRecommendation = min(upRecommendation, previous.Replicas)
+		}
+		if age <= downWindow {
+			downRecommendation = max(downRecommendation, previous.Replicas)
+		}
+	}
+
+	stabilized := current
+	if stabilized < upRecommendation {
+		stabilized = upRecommendation
+	}
+	if stabilized > downRecommendation {
+		stabilized = downRecommendation
+	}
+	switch {
+	case stabilized < recommendation:
+		return stabilized, fmt.Sprintf("scale up stabilization window of %s", upWindow)
+	case stabilized > recommendation:
+		return stabilized, fmt.Sprintf("scale down stabilization window of %s", downWindow)
+	}
+	return stabilized, ""
+}
+
+// GetCooldownWait returns how long scaling from the current to the desired replicas has to wait for the cooldown of the behavior
+func GetCooldownWait(behavior *v1alpha1.AutoscalerBehavior, lastScaleTime *metav1.Time, current int32, desired int32, now time.Time) time.Duration {
+	if behavior == nil || lastScaleTime == nil || desired == current {
+		return 0
+	}
+	rules := behavior.ScaleUp
+	if desired < current {
+		rules = behavior.ScaleDown
+	}
+	if rules == nil || rules.Cooldown == nil {
+		return 0
+	}
+	return max(0, lastScaleTime.Add(rules.Cooldown.Duration).Sub(now))
+}
+
+// recordRecommendation adds the recommendation to the status and drops the recommendations older than the window.
+// Only the last time of every recommended value is needed to find the lowest and highest value of a window,
+// so a value that is recommended again replaces its older entry.
+func recordRecommendation(status *v1alpha1.GameTypeAutoscalerStatus, recommendation int32, now time.Time, window time.Duration) {
+	if window <= 0 {
+		status.Recommendations = nil
+		return
+	}
+	recommendations := make([]v1alpha1.Recommendation, 0, len(status.Recommendations)+1)
+	for _, previous := range status.Recommendations {
+		if previous.Replicas == recommendation || now.Sub(previous.Time.Time) > window {
+			continue
+		}
+		recommendations = append(recommendations, previous)
+	}
+	recommendations = append(recommendations, v1alpha1.Recommendation{Replicas: recommendation, Time: metav1.NewTime(now)})
+	if len(recommendations) > MAX_RECOMMENDATIONS {
+		recommendations = recommendations[len(recommendations)-MAX_RECOMMENDATIONS:]
+	}
+	status.Recommendations = recommendations
+}
+
+// getStabilizationWindow returns the stabilization window of the rules, which is zero when it is not set
+func getStabilizationWindow(rules *v1alpha1.ScalingRules) time.Duration {
+	if rules == nil || rules.StabilizationWindow == nil {
+		return 0
+	}
+	return max(0, rules.StabilizationWindow.Duration)
+}
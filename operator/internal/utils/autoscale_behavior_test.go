@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Autoscale Behavior", func() {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	behavior := &v1alpha1.AutoscalerBehavior{
+		ScaleUp: &v1alpha1.ScalingRules{
+			StabilizationWindow: &metav1.Duration{Duration: time.Minute},
+			Cooldown:            &metav1.Duration{Duration: 30 * time.Second},
+		},
+		ScaleDown: &v1alpha1.ScalingRules{
+			StabilizationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+			Cooldown:            &metav1.Duration{Duration: 2 * time.Minute},
+		},
+	}
+
+	Context("When stabilizing a recommendation", func() {
+		It("Should apply recommendations right away without a behavior", func() {
+			status := &v1alpha1.GameTypeAutoscalerStatus{}
+			replicas, reason := StabilizeRecommendation(nil, status, 5, 2, now)
+			Expect(replicas).To(BeEquivalentTo(2))
+			Expect(reason).To(BeEmpty())
+			Expect(status.Recommendations).To(BeEmpty())
+		})
+
+		It("Should not follow flapping recommendations", func() {
+			status := &v1alpha1.GameTypeAutoscalerStatus{}
+			replicas, _ := StabilizeRecommendation(behavior, status, 5, 5, now)
+			Expect(replicas).To(BeEquivalentTo(5))
+
+			By("Keeping the replicas while a higher recommendation is in the scale down window")
+			replicas, reason := StabilizeRecommendation(behavior, status, 5, 2, now.Add(time.Minute))
+			Expect(replicas).To(BeEquivalentTo(5))
+			Expect(reason).To(Equal("scale down stabilization window of 5m0s"))
+
+			By("Scaling up only as far as the lowest recommendation of the scale up window")
+			replicas, reason = StabilizeRecommendation(behavior, status, 5, 8, now.Add(90*time.Second))
+			Expect(replicas).To(BeEquivalentTo(5))
+			Expect(reason).To(Equal("scale up stabilization window of 1m0s"))
+			replicas, _ = StabilizeRecommendation(behavior, status, 5, 8, now.Add(3*time.Minute))
+			Expect(replicas).To(BeEquivalentTo(8))
+
+			By("Scaling down once the higher recommendations left the window")
+			replicas, _ = StabilizeRecommendation(behavior, status, 8, 2, now.Add(9*time.Minute))
+			Expect(replicas).To(BeEquivalentTo(2))
+		})
+
+		It("Should only keep the last time of every recommendation within the window", func() {
+			status := &v1alpha1.GameTypeAutoscalerStatus{}
+			StabilizeRecommendation(behavior, status, 5, 5, now)
+			StabilizeRecommendation(behavior, status, 5, 6, now.Add(time.Minute))
+			StabilizeRecommendation(behavior, status, 5, 5, now.Add(2*time.Minute))
+			Expect(status.Recommendations).To(HaveLen(2))
+			Expect(status.Recommendations[1].Replicas).To(BeEquivalentTo(5))
+			Expect(status.Recommendations[1].Time.Time).To(Equal(now.Add(2 * time.Minute)))
+
+			StabilizeRecommendation(behavior, status, 5, 7, now.Add(10*time.Minute))
+			Expect(status.Recommendations).To(HaveLen(1))
+
+			for i := range MAX_RECOMMENDATIONS + 10 {
+				StabilizeRecommendation(behavior, status, 5, int32(i), now.Add(10*time.Minute))
+			}
+			Expect(status.Recommendations).To(HaveLen(MAX_RECOMMENDATIONS))
+		})
+	})
+
+	Context("When checking the cooldown", func() {
+		It("Should wait for the cooldown of the direction", func() {
+			lastScaleTime := metav1.NewTime(now)
+			Expect(GetCooldownWait(behavior, &lastScaleTime, 5, 8, now.Add(10*time.Second))).To(Equal(20 * time.Second))
+			Expect(GetCooldownWait(behavior, &lastScaleTime, 5, 8, now.Add(time.Minute))).To(BeZero())
+			Expect(GetCooldownWait(behavior, &lastScaleTime, 5, 2, now.Add(time.Minute))).To(Equal(time.Minute))
+		})
+
+		It("Should not wait without a cooldown or a previous scale", func() {
+			lastScaleTime := metav1.NewTime(now)
+			Expect(GetCooldownWait(nil, &lastScaleTime, 5, 8, now)).To(BeZero())
+			Expect(GetCooldownWait(behavior, nil, 5, 8, now)).To(BeZero())
+			Expect(GetCooldownWait(&v1alpha1.AutoscalerBehavior{}, &lastScaleTime, 5, 8, now)).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// GetOccupiedServerCount counts the servers that are allocated or have players on them
+// Servers that are being deleted are not counted, as they will not take new players
+func GetOccupiedServerCount(servers *v1alpha1.ServerList) int32 {
+	var occupied int32
+	for _, server := range servers.Items {
+		if server.DeletionTimestamp != nil {
+			continue
+		}
+		if server.Status.State == v1alpha1.ServerStateAllocated || server.Status.Players > 0 {
+			occupied++
+		}
+	}
+	return occupied
+}
+
+// GetBufferReplicas calculates how many replicas are needed to keep the buffer of free servers on top of the occupied servers
+// A percentage buffer is a share of the resulting replicas, so 20% with 8 occupied servers results in 10 replicas
+// The result is kept between the min and max replicas of the buffer spec
+func GetBufferReplicas(buffer *v1alpha1.BufferAutoscalerSpec, occupied int32) (int32, error) {
+	if buffer == nil {
+		return 0, fmt.Errorf("missing buffer spec")
+	}
+
+	var replicas int32
+	if buffer.BufferSize.Type == intstr.Int {
+		if buffer.BufferSize.IntVal < 0 {
+			return 0, fmt.Errorf("buffer size must not be negative: %d", buffer.BufferSize.IntVal)
+		}
+		replicas = occupied + buffer.BufferSize.IntVal
+	} else {
+		percent, err := intstr.GetScaledValueFromIntOrPercent(&buffer.BufferSize, 100, true)
+		if err != nil {
+			return 0, fmt.Errorf("invalid buffer size: %w", err)
+		}
+		if percent < 0 || percent >= 100 {
+			return 0, fmt.Errorf("buffer size must be between 0%% and 99%%: %s", buffer.BufferSize.StrVal)
+		}
+		replicas = int32(math.Ceil(float64(occupied) * 100 / float64(100-percent)))
+	}
+
+	if buffer.MinReplicas != nil {
+		replicas = max(replicas, *buffer.MinReplicas)
+	}
+	if buffer.MaxReplicas != nil {
+		replicas = min(replicas, *buffer.MaxReplicas)
+	}
+	return replicas, nil
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Buffer Autoscaling", func() {
+	Context("When counting occupied servers", func() {
+		It("Should count allocated servers and servers with players", func() {
+			deletionTime := metav1.Now()
+			servers := &v1alpha1.ServerList{Items: []v1alpha1.Server{
+				{Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateAllocated}},
+				{Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady, Players: 3}},
+				{Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady}},
+				{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deletionTime}, Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateAllocated}},
+			}}
+			Expect(GetOccupiedServerCount(servers)).To(Equal(int32(2)))
+		})
+	})
+
+	Context("When calculating the buffer replicas", func() {
+		It("Should add an absolute buffer", func() {
+			replicas, err := GetBufferReplicas(&v1alpha1.BufferAutoscalerSpec{BufferSize: intstr.FromInt32(5)}, 3)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(8)))
+		})
+
+		It("Should keep a percentage of the replicas free", func() {
+			replicas, err := GetBufferReplicas(&v1alpha1.BufferAutoscalerSpec{BufferSize: intstr.FromString("20%")}, 8)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(10)))
+
+			replicas, err = GetBufferReplicas(&v1alpha1.BufferAutoscalerSpec{BufferSize: intstr.FromString("50%")}, 3)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(6)))
+		})
+
+		It("Should stay between the min and max replicas", func() {
+			minReplicas := int32(4)
+			maxReplicas := int32(6)
+			buffer := &v1alpha1.BufferAutoscalerSpec{BufferSize: intstr.FromInt32(1), MinReplicas: &minReplicas, MaxReplicas: &maxReplicas}
+			replicas, err := GetBufferReplicas(buffer, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(4)))
+
+			replicas, err = GetBufferReplicas(buffer, 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(6)))
+		})
+
+		It("Should fail on invalid buffers", func() {
+			_, err := GetBufferReplicas(nil, 1)
+			Expect(err).To(HaveOccurred())
+			_, err = GetBufferReplicas(&v1alpha1.BufferAutoscalerSpec{BufferSize: intstr.FromString("100%")}, 1)
+			Expect(err).To(HaveOccurred())
+			_, err = GetBufferReplicas(&v1alpha1.BufferAutoscalerSpec{BufferSize: intstr.FromString("lots")}, 1)
+			Expect(err).To(HaveOccurred())
+			_, err = GetBufferReplicas(&v1alpha1.BufferAutoscalerSpec{BufferSize: intstr.FromInt32(-1)}, 1)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
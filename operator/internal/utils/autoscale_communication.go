@@ -2,31 +2,56 @@ package utils
 
 import (
 	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
 	"io"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"time"
 )
 
+// WEBHOOK_SIGNATURE_HEADER is the header with the HMAC signature of the request body
+const WEBHOOK_SIGNATURE_HEADER = "X-Fallernetes-Signature"
+
 type Webhook interface {
-	SendScaleWebhookRequest(autoscaler *v1alpha1.GameTypeAutoscaler, gametype *v1alpha1.GameType) (AutoscaleResponse, error)
+	SendScaleWebhookRequest(ctx context.Context, autoscaler *v1alpha1.GameTypeAutoscaler, target *ScaleTarget) (AutoscaleResponse, error)
 }
 
-type ProductionWebhookRequest struct{}
+// ProductionWebhookRequest sends the requests to the webhooks, the client is used to read the servers of the target
+// The secrets of the webhook are read with the secret reader, which should not be cached so the operator does not watch every secret
+type ProductionWebhookRequest struct {
+	Client       client.Client
+	SecretReader client.Reader
+}
 
-func (w ProductionWebhookRequest) SendScaleWebhookRequest(autoscaler *v1alpha1.GameTypeAutoscaler,
-	gametype *v1alpha1.GameType) (AutoscaleResponse, error) {
+func (w ProductionWebhookRequest) SendScaleWebhookRequest(ctx context.Context, autoscaler *v1alpha1.GameTypeAutoscaler,
+	target *ScaleTarget) (AutoscaleResponse, error) {
 	autoscalerSpec := autoscaler.Spec.AutoscalePolicy.WebhookAutoscalerSpec
+	if autoscalerSpec == nil {
+		return AutoscaleResponse{}, errors.New("missing webhook spec")
+	}
+	secure := autoscalerSpec.CABundle != nil || autoscalerSpec.ClientCertSecretRef != nil
 
 	var url string
 	if autoscalerSpec.Url != nil {
 		url = *autoscalerSpec.Url
 	} else {
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
 		service := autoscalerSpec.Service
-		url = fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", service.Name, service.Namespace, service.Port)
+		url = fmt.Sprintf("%s://%s.%s.svc.cluster.local:%d", scheme, service.Name, service.Namespace, service.Port)
 	}
 	if autoscalerSpec.Path == nil {
 		return AutoscaleResponse{}, errors.New("missing path")
@@ -37,20 +62,32 @@ func (w ProductionWebhookRequest) SendScaleWebhookRequest(autoscaler *v1alpha1.G
 	httpClient := &http.Client{
 		Timeout: 10 * time.Second,
 	}
+	if secure {
+		tlsConfig, err := getTLSConfig(ctx, w.SecretReader, autoscalerSpec.CABundle, autoscalerSpec.ClientCertSecretRef, autoscaler.Namespace)
+		if err != nil {
+			return AutoscaleResponse{}, fmt.Errorf("failed to configure tls: %w", err)
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
 
-	request := AutoscaleRequest{
-		GameName:        autoscaler.Spec.GameTypeName,
-		CurrentReplicas: int(gametype.Spec.FleetSpec.Scaling.Replicas),
+	servers, err := getTargetServers(ctx, w.Client, target)
+	if err != nil {
+		return AutoscaleResponse{}, err
 	}
+	request := BuildAutoscaleRequest(autoscaler, target, servers)
 	requestBody, err := json.Marshal(request)
 	if err != nil {
 		return AutoscaleResponse{}, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
 	if err != nil {
 		return AutoscaleResponse{}, err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := w.addAuthHeaders(ctx, req, requestBody, autoscalerSpec, autoscaler.Namespace); err != nil {
+		return AutoscaleResponse{}, fmt.Errorf("failed to add authentication: %w", err)
+	}
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
@@ -76,9 +113,176 @@ func (w ProductionWebhookRequest) SendScaleWebhookRequest(autoscaler *v1alpha1.G
 	return response, nil
 }
 
+// getTLSConfig builds the tls config of an autoscaler from the ca bundle and the client certificate secret
+// Without a ca bundle, the system roots are used to verify the server
+func getTLSConfig(ctx context.Context, c client.Reader, caBundle []byte, clientCertRef *corev1.LocalObjectReference, namespace string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caBundle != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, errors.New("the ca bundle does not contain any valid certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if clientCertRef != nil {
+		secret, err := getAutoscalerSecret(ctx, c, clientCertRef.Name, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client certificate secret: %w", err)
+		}
+		certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate in secret %s: %w", secret.Name, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
+
+// addAuthHeaders adds the bearer token, the extra headers and the signature of the body to the webhook request
+func (w ProductionWebhookRequest) addAuthHeaders(ctx context.Context, req *http.Request, body []byte, spec *v1alpha1.WebhookAutoscalerSpec, namespace string) error {
+	if spec.BearerTokenSecretRef != nil {
+		token, err := w.getSecretValue(ctx, spec.BearerTokenSecretRef, namespace)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for _, header := range spec.Headers {
+		value, err := w.getSecretValue(ctx, &header.ValueFrom, namespace)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(header.Name, value)
+	}
+	if spec.HMACSecretRef != nil {
+		key, err := w.getSecretValue(ctx, spec.HMACSecretRef, namespace)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(WEBHOOK_SIGNATURE_HEADER, SignWebhookBody([]byte(key), body))
+	}
+	return nil
+}
+
+// getSecretValue reads a single key of a secret in the namespace
+func (w ProductionWebhookRequest) getSecretValue(ctx context.Context, selector *corev1.SecretKeySelector, namespace string) (string, error) {
+	secret, err := getAutoscalerSecret(ctx, w.SecretReader, selector.Name, namespace)
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %s", selector.Name, selector.Key)
+	}
+	return string(value), nil
+}
+
+// getAutoscalerSecret reads a secret referenced by an autoscaler, which is only allowed when it has the autoscaler secret label.
+// Whoever creates an autoscaler chooses where its secrets are sent, so secrets without the label are never read.
+func getAutoscalerSecret(ctx context.Context, c client.Reader, name string, namespace string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", name, err)
+	}
+	if secret.Labels[v1alpha1.AutoscalerSecretLabel] != "true" {
+		return nil, fmt.Errorf("secret %s is not labeled %s=true, so autoscalers may not use it", name, v1alpha1.AutoscalerSecretLabel)
+	}
+	return secret, nil
+}
+
+// SignWebhookBody returns the signature of the body sent in the signature header, which the webhook can verify
+func SignWebhookBody(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// AUTOSCALE_REQUEST_VERSION is the version of the payload sent to the webhooks
+// Fields are only added within a version, so receivers can ignore the ones they do not know
+const AUTOSCALE_REQUEST_VERSION = "v2"
+
 type AutoscaleRequest struct {
-	GameName        string `json:"game_name"`
-	CurrentReplicas int    `json:"current_replicas"`
+	Version string `json:"version"`
+	// The game of the target, it is empty for fleets that do not belong to a gametype
+	GameName string `json:"game_name"`
+	// The kind and name of the resource the autoscaler scales
+	TargetKind      v1alpha1.TargetKind `json:"target_kind"`
+	TargetName      string              `json:"target_name"`
+	Namespace       string              `json:"namespace"`
+	FleetName       string              `json:"fleet_name"`
+	CurrentReplicas int                 `json:"current_replicas"`
+	// The limits of the autoscaler, which are applied to the response
+	MinReplicas *int32 `json:"min_replicas,omitempty"`
+	MaxReplicas *int32 `json:"max_replicas,omitempty"`
+	// The servers of the target, for a gametype including the old fleets during an update
+	ServerCounts AutoscaleServerCounts `json:"server_counts"`
+	Servers      []AutoscaleServer     `json:"servers"`
+}
+
+type AutoscaleServerCounts struct {
+	Total int `json:"total"`
+	// The amount of servers in every lifecycle phase
+	ByState map[v1alpha1.ServerState]int `json:"by_state"`
+	// The amount of servers that allowed their deletion
+	DeleteAllowed int   `json:"delete_allowed"`
+	Players       int32 `json:"players"`
+	Capacity      int32 `json:"capacity"`
+}
+
+type AutoscaleServer struct {
+	Name          string               `json:"name"`
+	FleetName     string               `json:"fleet_name"`
+	State         v1alpha1.ServerState `json:"state"`
+	DeleteAllowed bool                 `json:"delete_allowed"`
+	Players       int32                `json:"players"`
+	// Only set when the game info of the server has a capacity
+	Capacity *int `json:"capacity,omitempty"`
+}
+
+// BuildAutoscaleRequest builds the payload of the webhook from the target and its servers
+// Servers without a state yet are counted as creating, and servers are delete allowed once their game allowed it,
+// even while they are still ready
+func BuildAutoscaleRequest(autoscaler *v1alpha1.GameTypeAutoscaler, target *ScaleTarget, servers *v1alpha1.ServerList) AutoscaleRequest {
+	request := AutoscaleRequest{
+		Version:         AUTOSCALE_REQUEST_VERSION,
+		GameName:        target.GameName,
+		TargetKind:      target.Kind,
+		TargetName:      target.Name,
+		Namespace:       target.Namespace,
+		FleetName:       target.FleetName,
+		CurrentReplicas: int(target.Replicas),
+		MinReplicas:     autoscaler.Spec.MinReplicas,
+		MaxReplicas:     autoscaler.Spec.MaxReplicas,
+		ServerCounts: AutoscaleServerCounts{
+			Total:   len(servers.Items),
+			ByState: make(map[v1alpha1.ServerState]int),
+		},
+		Servers: make([]AutoscaleServer, 0, len(servers.Items)),
+	}
+	request.ServerCounts.Players, request.ServerCounts.Capacity = GetPlayerTotals(servers)
+	for _, server := range servers.Items {
+		state := server.Status.State
+		if state == "" {
+			state = v1alpha1.ServerStateCreating
+		}
+		request.ServerCounts.ByState[state]++
+		deleteAllowed := server.Status.DeleteAllowed || state == v1alpha1.ServerStateDeleteAllowed
+		if deleteAllowed {
+			request.ServerCounts.DeleteAllowed++
+		}
+		autoscaleServer := AutoscaleServer{
+			Name:          server.Name,
+			FleetName:     server.Labels["fleet"],
+			State:         state,
+			DeleteAllowed: deleteAllowed,
+			Players:       server.Status.Players,
+		}
+		if server.Spec.GameInfo != nil {
+			autoscaleServer.Capacity = server.Spec.GameInfo.Capacity
+		}
+		request.Servers = append(request.Servers, autoscaleServer)
+	}
+	return request
 }
 
 type AutoscaleResponse struct {
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Autoscale Webhook Testing", func() {
+	Context("When sending a webhook request", func() {
+		ctx := context.Background()
+		path := "scale"
+
+		newAutoscaler := func(spec *v1alpha1.WebhookAutoscalerSpec) *v1alpha1.GameTypeAutoscaler {
+			return &v1alpha1.GameTypeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "default"},
+				Spec: v1alpha1.GameTypeAutoscalerSpec{
+					GameTypeName:    "game",
+					AutoscalePolicy: v1alpha1.AutoscalePolicy{Type: v1alpha1.Webhook, WebhookAutoscalerSpec: spec},
+				},
+			}
+		}
+		newClient := func(objects ...client.Object) client.Client {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		}
+		secretKey := func(name, key string) *corev1.SecretKeySelector {
+			return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+		}
+		secretLabels := map[string]string{v1alpha1.AutoscalerSecretLabel: "true"}
+
+		It("Authenticates the request with secrets", func() {
+			var headers http.Header
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				headers = r.Header
+				body, _ = io.ReadAll(r.Body)
+				_ = json.NewEncoder(w).Encode(AutoscaleResponse{Scale: true, DesiredReplicas: 3})
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "webhook-auth", Namespace: "default", Labels: secretLabels},
+				Data: map[string][]byte{
+					"token":  []byte("my-token"),
+					"tenant": []byte("falloria"),
+					"hmac":   []byte("signing-key"),
+				},
+			}
+			c := newClient(secret)
+			webhook := ProductionWebhookRequest{Client: c, SecretReader: c}
+			autoscaler := newAutoscaler(&v1alpha1.WebhookAutoscalerSpec{
+				Url:                  &server.URL,
+				Path:                 &path,
+				BearerTokenSecretRef: secretKey("webhook-auth", "token"),
+				Headers:              []v1alpha1.WebhookHeader{{Name: "X-Tenant", ValueFrom: *secretKey("webhook-auth", "tenant")}},
+				HMACSecretRef:        secretKey("webhook-auth", "hmac"),
+			})
+
+			target := NewGameTypeTarget(&v1alpha1.GameType{ObjectMeta: metav1.ObjectMeta{Name: "game", Namespace: "default"}})
+			response, err := webhook.SendScaleWebhookRequest(ctx, autoscaler, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.DesiredReplicas).To(Equal(3))
+			Expect(headers.Get("Authorization")).To(Equal("Bearer my-token"))
+			Expect(headers.Get("X-Tenant")).To(Equal("falloria"))
+			Expect(headers.Get(WEBHOOK_SIGNATURE_HEADER)).To(Equal(SignWebhookBody([]byte("signing-key"), body)))
+			request := AutoscaleRequest{}
+			Expect(json.Unmarshal(body, &request)).To(Succeed())
+			Expect(request.Version).To(Equal(AUTOSCALE_REQUEST_VERSION))
+			Expect(request.GameName).To(Equal("game"))
+			Expect(request.TargetKind).To(Equal(v1alpha1.GameTypeTarget))
+
+			By("Failing when a secret key is missing")
+			autoscaler.Spec.AutoscalePolicy.WebhookAutoscalerSpec.HMACSecretRef = secretKey("webhook-auth", "missing")
+			_, err = webhook.SendScaleWebhookRequest(ctx, autoscaler, target)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Refuses to send secrets without the autoscaler secret label", func() {
+			called := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				_ = json.NewEncoder(w).Encode(AutoscaleResponse{Scale: true, DesiredReplicas: 3})
+			}))
+			defer server.Close()
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("hunter2")},
+			}
+			c := newClient(secret)
+			webhook := ProductionWebhookRequest{Client: c, SecretReader: c}
+			autoscaler := newAutoscaler(&v1alpha1.WebhookAutoscalerSpec{
+				Url:     &server.URL,
+				Path:    &path,
+				Headers: []v1alpha1.WebhookHeader{{Name: "X-Leak", ValueFrom: *secretKey("database", "password")}},
+			})
+			_, err := webhook.SendScaleWebhookRequest(ctx, autoscaler, NewGameTypeTarget(&v1alpha1.GameType{}))
+			Expect(err).To(MatchError(ContainSubstring(v1alpha1.AutoscalerSecretLabel)))
+			Expect(called).To(BeFalse())
+
+			By("Refusing the label with another value")
+			secret.Labels = map[string]string{v1alpha1.AutoscalerSecretLabel: "false"}
+			c = newClient(secret)
+			webhook = ProductionWebhookRequest{Client: c, SecretReader: c}
+			_, err = webhook.SendScaleWebhookRequest(ctx, autoscaler, NewGameTypeTarget(&v1alpha1.GameType{}))
+			Expect(err).To(HaveOccurred())
+			Expect(called).To(BeFalse())
+		})
+
+		It("Verifies the webhook with the ca bundle and presents the client certificate", func() {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(AutoscaleResponse{Scale: true, DesiredReplicas: 5})
+			}))
+			server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+			server.StartTLS()
+			defer server.Close()
+
+			By("Reusing the certificate of the test server as the client certificate")
+			certificate := server.TLS.Certificates[0]
+			caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]})
+			key, err := x509.MarshalPKCS8PrivateKey(certificate.PrivateKey)
+			Expect(err).ToNot(HaveOccurred())
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "webhook-cert", Namespace: "default", Labels: secretLabels},
+				Type:       corev1.SecretTypeTLS,
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       caBundle,
+					corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}),
+				},
+			}
+			c := newClient(secret)
+			webhook := ProductionWebhookRequest{Client: c, SecretReader: c}
+			autoscaler := newAutoscaler(&v1alpha1.WebhookAutoscalerSpec{
+				Url:                 &server.URL,
+				Path:                &path,
+				CABundle:            caBundle,
+				ClientCertSecretRef: &corev1.LocalObjectReference{Name: "webhook-cert"},
+			})
+
+			response, err := webhook.SendScaleWebhookRequest(ctx, autoscaler, NewGameTypeTarget(&v1alpha1.GameType{}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.DesiredReplicas).To(Equal(5))
+
+			By("Failing without the client certificate")
+			autoscaler.Spec.AutoscalePolicy.WebhookAutoscalerSpec.ClientCertSecretRef = nil
+			_, err = webhook.SendScaleWebhookRequest(ctx, autoscaler, NewGameTypeTarget(&v1alpha1.GameType{}))
+			Expect(err).To(HaveOccurred())
+
+			By("Failing with an invalid ca bundle")
+			autoscaler.Spec.AutoscalePolicy.WebhookAutoscalerSpec.CABundle = []byte("not a certificate")
+			_, err = webhook.SendScaleWebhookRequest(ctx, autoscaler, NewGameTypeTarget(&v1alpha1.GameType{}))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("When building the webhook payload", func() {
+		It("Counts the servers of the gametype", func() {
+			capacity := 10
+			minReplicas := int32(2)
+			autoscaler := &v1alpha1.GameTypeAutoscaler{Spec: v1alpha1.GameTypeAutoscalerSpec{GameTypeName: "game", MinReplicas: &minReplicas}}
+			gametype := &v1alpha1.GameType{
+				ObjectMeta: metav1.ObjectMeta{Name: "game", Namespace: "games"},
+				Spec:       v1alpha1.GameTypeSpec{FleetSpec: v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{Replicas: 3}}},
+				Status:     v1alpha1.GameTypeStatus{CurrentFleetName: "game-new"},
+			}
+			servers := &v1alpha1.ServerList{Items: []v1alpha1.Server{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "server1", Labels: map[string]string{"fleet": "game-new"}},
+					Spec:       v1alpha1.ServerSpec{GameInfo: &v1alpha1.GameInfo{Capacity: &capacity}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateAllocated, Players: 4},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "server2", Labels: map[string]string{"fleet": "game-new"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "server3", Labels: map[string]string{"fleet": "game-old"}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateDeleteAllowed},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "server4", Labels: map[string]string{"fleet": "game-new"}},
+					Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady, DeleteAllowed: true},
+				},
+			}}
+
+			request := BuildAutoscaleRequest(autoscaler, NewGameTypeTarget(gametype), servers)
+			Expect(request.Version).To(Equal(AUTOSCALE_REQUEST_VERSION))
+			Expect(request.Namespace).To(Equal("games"))
+			Expect(request.FleetName).To(Equal("game-new"))
+			Expect(request.CurrentReplicas).To(Equal(3))
+			Expect(*request.MinReplicas).To(BeEquivalentTo(2))
+			Expect(request.MaxReplicas).To(BeNil())
+			Expect(request.ServerCounts.Total).To(Equal(4))
+			Expect(request.ServerCounts.ByState).To(Equal(map[v1alpha1.ServerState]int{
+				v1alpha1.ServerStateAllocated:     1,
+				v1alpha1.ServerStateCreating:      1,
+				v1alpha1.ServerStateDeleteAllowed: 1,
+				v1alpha1.ServerStateReady:         1,
+			}))
+			Expect(request.ServerCounts.DeleteAllowed).To(Equal(2))
+			Expect(request.ServerCounts.Players).To(BeEquivalentTo(4))
+			Expect(request.ServerCounts.Capacity).To(BeEquivalentTo(10))
+			Expect(request.Servers).To(HaveLen(4))
+			Expect(*request.Servers[0].Capacity).To(Equal(10))
+			Expect(request.Servers[1].Capacity).To(BeNil())
+			Expect(request.Servers[2].FleetName).To(Equal("game-old"))
+			Expect(request.Servers[2].DeleteAllowed).To(BeTrue())
+			Expect(request.Servers[0].DeleteAllowed).To(BeFalse())
+
+			By("Counting a ready server whose game allowed deletion")
+			Expect(request.Servers[3].State).To(Equal(v1alpha1.ServerStateReady))
+			Expect(request.Servers[3].DeleteAllowed).To(BeTrue())
+		})
+	})
+})
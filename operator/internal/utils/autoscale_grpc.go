@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	autoscalerv1 "github.com/MirrorStudios/fallernetes/api/proto/autoscaler/v1"
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type GrpcAutoscaler interface {
+	SendScaleGrpcRequest(ctx context.Context, autoscaler *v1alpha1.GameTypeAutoscaler, target *ScaleTarget) (AutoscaleResponse, error)
+}
+
+// ProductionGrpcRequest calls the grpc autoscalers, the client is used to read the servers of the target
+// The client certificate secret is read with the secret reader, like for the webhooks
+type ProductionGrpcRequest struct {
+	Client       client.Client
+	SecretReader client.Reader
+}
+
+// SendScaleGrpcRequest calls the Scale method of the grpc autoscaler with the same data as the webhook payload
+func (g ProductionGrpcRequest) SendScaleGrpcRequest(ctx context.Context, autoscaler *v1alpha1.GameTypeAutoscaler,
+	target *ScaleTarget) (AutoscaleResponse, error) {
+	grpcSpec := autoscaler.Spec.AutoscalePolicy.GrpcAutoscalerSpec
+	if grpcSpec == nil {
+		return AutoscaleResponse{}, errors.New("missing grpc spec")
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if grpcSpec.CABundle != nil || grpcSpec.ClientCertSecretRef != nil {
+		tlsConfig, err := getTLSConfig(ctx, g.SecretReader, grpcSpec.CABundle, grpcSpec.ClientCertSecretRef, autoscaler.Namespace)
+		if err != nil {
+			return AutoscaleResponse{}, fmt.Errorf("failed to configure tls: %w", err)
+		}
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(grpcSpec.Address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return AutoscaleResponse{}, err
+	}
+	defer conn.Close()
+
+	servers, err := getTargetServers(ctx, g.Client, target)
+	if err != nil {
+		return AutoscaleResponse{}, err
+	}
+	request := ToScaleRequest(BuildAutoscaleRequest(autoscaler, target, servers))
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	response, err := autoscalerv1.NewAutoscalerClient(conn).Scale(ctx, request)
+	if err != nil {
+		return AutoscaleResponse{}, err
+	}
+	return AutoscaleResponse{
+		Scale:           response.GetScale(),
+		DesiredReplicas: int(response.GetDesiredReplicas()),
+	}, nil
+}
+
+// ToScaleRequest converts the webhook payload to the message of the grpc autoscaler
+func ToScaleRequest(request AutoscaleRequest) *autoscalerv1.ScaleRequest {
+	scaleRequest := &autoscalerv1.ScaleRequest{
+		Version:         request.Version,
+		GameName:        request.GameName,
+		TargetKind:      string(request.TargetKind),
+		TargetName:      request.TargetName,
+		Namespace:       request.Namespace,
+		FleetName:       request.FleetName,
+		CurrentReplicas: int32(request.CurrentReplicas),
+		MinReplicas:     request.MinReplicas,
+		MaxReplicas:     request.MaxReplicas,
+		ServerCounts: &autoscalerv1.ServerCounts{
+			Total:         int32(request.ServerCounts.Total),
+			ByState:       make(map[string]int32, len(request.ServerCounts.ByState)),
+			DeleteAllowed: int32(request.ServerCounts.DeleteAllowed),
+			Players:       request.ServerCounts.Players,
+			Capacity:      request.ServerCounts.Capacity,
+		},
+		Servers: make([]*autoscalerv1.Server, 0, len(request.Servers)),
+	}
+	for state, count := range request.ServerCounts.ByState {
+		scaleRequest.ServerCounts.ByState[string(state)] = int32(count)
+	}
+	for _, server := range request.Servers {
+		grpcServer := &autoscalerv1.Server{
+			Name:          server.Name,
+			FleetName:     server.FleetName,
+			State:         string(server.State),
+			DeleteAllowed: server.DeleteAllowed,
+			Players:       server.Players,
+		}
+		if server.Capacity != nil {
+			capacity := int32(*server.Capacity)
+			grpcServer.Capacity = &capacity
+		}
+		scaleRequest.Servers = append(scaleRequest.Servers, grpcServer)
+	}
+	return scaleRequest
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"net"
+
+	autoscalerv1 "github.com/MirrorStudios/fallernetes/api/proto/autoscaler/v1"
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeGrpcAutoscaler struct {
+	autoscalerv1.UnimplementedAutoscalerServer
+	requests []*autoscalerv1.ScaleRequest
+	fail     bool
+}
+
+func (f *fakeGrpcAutoscaler) Scale(ctx context.Context, request *autoscalerv1.ScaleRequest) (*autoscalerv1.ScaleResponse, error) {
+	if f.fail {
+		return nil, status.Error(codes.Unavailable, "scaler is down")
+	}
+	f.requests = append(f.requests, request)
+	return &autoscalerv1.ScaleResponse{Scale: true, DesiredReplicas: request.CurrentReplicas + 2}, nil
+}
+
+// startGrpcAutoscaler starts an in-process grpc server with the fake autoscaler, and returns its address
+func startGrpcAutoscaler(autoscaler *fakeGrpcAutoscaler) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	server := grpc.NewServer()
+	autoscalerv1.RegisterAutoscalerServer(server, autoscaler)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return listener.Addr().String(), server.Stop
+}
+
+var _ = Describe("Grpc Autoscale Testing", func() {
+	Context("When sending a grpc request", func() {
+		ctx := context.Background()
+
+		It("Calls the autoscaler with the servers of the gametype", func() {
+			fakeAutoscaler := &fakeGrpcAutoscaler{}
+			address, stop := startGrpcAutoscaler(fakeAutoscaler)
+			defer stop()
+
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			capacity := 8
+			server := &v1alpha1.Server{
+				ObjectMeta: metav1.ObjectMeta{Name: "server1", Namespace: "default", Labels: map[string]string{"gametype": "game", "fleet": "game-fleet"}},
+				Spec:       v1alpha1.ServerSpec{GameInfo: &v1alpha1.GameInfo{Capacity: &capacity}},
+				Status:     v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady, Players: 3},
+			}
+			grpcRequest := ProductionGrpcRequest{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(server).WithStatusSubresource(server).Build()}
+			autoscaler := &v1alpha1.GameTypeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "default"},
+				Spec: v1alpha1.GameTypeAutoscalerSpec{
+					GameTypeName:    "game",
+					AutoscalePolicy: v1alpha1.AutoscalePolicy{Type: v1alpha1.Grpc, GrpcAutoscalerSpec: &v1alpha1.GrpcAutoscalerSpec{Address: address}},
+				},
+			}
+			gametype := &v1alpha1.GameType{
+				ObjectMeta: metav1.ObjectMeta{Name: "game", Namespace: "default"},
+				Spec:       v1alpha1.GameTypeSpec{FleetSpec: v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{Replicas: 1}}},
+			}
+
+			response, err := grpcRequest.SendScaleGrpcRequest(ctx, autoscaler, NewGameTypeTarget(gametype))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response).To(Equal(AutoscaleResponse{Scale: true, DesiredReplicas: 3}))
+			Expect(fakeAutoscaler.requests).To(HaveLen(1))
+			request := fakeAutoscaler.requests[0]
+			Expect(request.GetVersion()).To(Equal(AUTOSCALE_REQUEST_VERSION))
+			Expect(request.GetGameName()).To(Equal("game"))
+			Expect(request.GetServerCounts().GetByState()).To(HaveKeyWithValue("Ready", int32(1)))
+			Expect(request.GetServers()).To(HaveLen(1))
+			Expect(request.GetServers()[0].GetFleetName()).To(Equal("game-fleet"))
+			Expect(request.GetServers()[0].GetPlayers()).To(BeEquivalentTo(3))
+			Expect(request.GetServers()[0].GetCapacity()).To(BeEquivalentTo(8))
+
+			By("Failing when the autoscaler returns an error")
+			fakeAutoscaler.fail = true
+			_, err = grpcRequest.SendScaleGrpcRequest(ctx, autoscaler, NewGameTypeTarget(gametype))
+			Expect(status.Code(err)).To(Equal(codes.Unavailable))
+
+			By("Failing without a grpc spec")
+			autoscaler.Spec.AutoscalePolicy.GrpcAutoscalerSpec = nil
+			_, err = grpcRequest.SendScaleGrpcRequest(ctx, autoscaler, NewGameTypeTarget(gametype))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When converting the payload", func() {
+		It("Keeps the optional fields unset", func() {
+			request := ToScaleRequest(AutoscaleRequest{
+				Version:      AUTOSCALE_REQUEST_VERSION,
+				ServerCounts: AutoscaleServerCounts{ByState: map[v1alpha1.ServerState]int{}},
+				Servers:      []AutoscaleServer{{Name: "server1"}},
+			})
+			Expect(request.MinReplicas).To(BeNil())
+			Expect(request.MaxReplicas).To(BeNil())
+			Expect(request.GetServers()[0].Capacity).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ClampReplicas applies the step limits and the min and max replicas of the autoscaler to a recommendation.
+// The step limits are applied first, so the min and max replicas always hold.
+// It returns the replicas to scale to, and why the recommendation was clamped, which is empty when it was not.
+func ClampReplicas(spec *v1alpha1.GameTypeAutoscalerSpec, current int32, desired int32) (int32, string, error) {
+	replicas := desired
+	var reason string
+
+	if desired > current && spec.MaxScaleUpStep != nil {
+		step, err := getScaleStep(spec.MaxScaleUpStep, current)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid max scale up step: %w", err)
+		}
+		if replicas > current+step {
+			replicas = current + step
+			reason = fmt.Sprintf("max scale up step of %d", step)
+		}
+	}
+	if desired < current && spec.MaxScaleDownStep != nil {
+		step, err := getScaleStep(spec.MaxScaleDownStep, current)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid max scale down step: %w", err)
+		}
+		if replicas < current-step {
+			replicas = current - step
+			reason = fmt.Sprintf("max scale down step of %d", step)
+		}
+	}
+
+	if spec.MinReplicas != nil && replicas < *spec.MinReplicas {
+		replicas = *spec.MinReplicas
+		reason = fmt.Sprintf("min replicas of %d", *spec.MinReplicas)
+	}
+	if spec.MaxReplicas != nil && replicas > *spec.MaxReplicas {
+		replicas = *spec.MaxReplicas
+		reason = fmt.Sprintf("max replicas of %d", *spec.MaxReplicas)
+	}
+	return replicas, reason, nil
+}
+
+// getScaleStep resolves a step limit against the current replicas, rounding up and allowing at least one replica
+func getScaleStep(step *intstr.IntOrString, current int32) (int32, error) {
+	value, err := intstr.GetScaledValueFromIntOrPercent(step, int(current), true)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("step must not be negative: %d", value)
+	}
+	return max(1, int32(value)), nil
+}
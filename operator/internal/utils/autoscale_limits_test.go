@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Autoscale Limits", func() {
+	Context("When clamping a recommendation", func() {
+		It("Should not change recommendations within the limits", func() {
+			replicas, reason, err := ClampReplicas(&v1alpha1.GameTypeAutoscalerSpec{}, 5, 10000)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(10000)))
+			Expect(reason).To(BeEmpty())
+		})
+
+		It("Should keep the replicas between min and max", func() {
+			minReplicas := int32(2)
+			maxReplicas := int32(20)
+			spec := &v1alpha1.GameTypeAutoscalerSpec{MinReplicas: &minReplicas, MaxReplicas: &maxReplicas}
+			replicas, reason, err := ClampReplicas(spec, 5, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(2)))
+			Expect(reason).To(Equal("min replicas of 2"))
+
+			replicas, reason, err = ClampReplicas(spec, 5, 10000)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(20)))
+			Expect(reason).To(Equal("max replicas of 20"))
+		})
+
+		It("Should limit the step size", func() {
+			up := intstr.FromString("50%")
+			down := intstr.FromInt32(3)
+			spec := &v1alpha1.GameTypeAutoscalerSpec{MaxScaleUpStep: &up, MaxScaleDownStep: &down}
+			replicas, reason, err := ClampReplicas(spec, 10, 100)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(15)))
+			Expect(reason).To(Equal("max scale up step of 5"))
+
+			replicas, _, err = ClampReplicas(spec, 10, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(7)))
+
+			By("Always allowing one replica, so an empty gametype can grow")
+			replicas, _, err = ClampReplicas(spec, 0, 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(1)))
+		})
+
+		It("Should apply the min and max replicas after the step", func() {
+			minReplicas := int32(5)
+			step := intstr.FromInt32(1)
+			spec := &v1alpha1.GameTypeAutoscalerSpec{MinReplicas: &minReplicas, MaxScaleUpStep: &step}
+			replicas, reason, err := ClampReplicas(spec, 0, 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(5)))
+			Expect(reason).To(Equal("min replicas of 5"))
+		})
+
+		It("Should fail on invalid steps", func() {
+			step := intstr.FromString("fast")
+			_, _, err := ClampReplicas(&v1alpha1.GameTypeAutoscalerSpec{MaxScaleUpStep: &step}, 1, 10)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
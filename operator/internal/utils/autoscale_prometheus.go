@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+)
+
+type PrometheusQuerier interface {
+	Query(ctx context.Context, spec *v1alpha1.PrometheusAutoscalerSpec) (float64, error)
+}
+
+type ProductionPrometheusQuery struct{}
+
+// Query runs the instant query of the spec against the query api of the prometheus server
+// The query has to return a scalar or a vector with a single sample
+func (p ProductionPrometheusQuery) Query(ctx context.Context, spec *v1alpha1.PrometheusAutoscalerSpec) (float64, error) {
+	if spec == nil {
+		return 0, errors.New("missing prometheus spec")
+	}
+	form := url.Values{"query": {spec.Query}}
+	queryUrl := strings.TrimSuffix(spec.ServerUrl, "/") + "/api/v1/query"
+	req, err := http.NewRequestWithContext(ctx, "POST", queryUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var response prometheusResponse
+	if err := json.Unmarshal(bodyBytes, &response); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w. Raw response: %s", err, string(bodyBytes))
+	}
+	if response.Status != "success" {
+		return 0, fmt.Errorf("query failed with %d: %s: %s", resp.StatusCode, response.ErrorType, response.Error)
+	}
+	return getPrometheusValue(response.Data)
+}
+
+type prometheusResponse struct {
+	Status    string         `json:"status"`
+	Data      prometheusData `json:"data"`
+	ErrorType string         `json:"errorType"`
+	Error     string         `json:"error"`
+}
+
+type prometheusData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// prometheusSample is a [timestamp, "value"] pair of the query api
+type prometheusSample [2]interface{}
+
+// getPrometheusValue reads the single value out of the result of a query
+func getPrometheusValue(data prometheusData) (float64, error) {
+	var sample prometheusSample
+	switch data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(data.Result, &sample); err != nil {
+			return 0, fmt.Errorf("invalid scalar result: %w", err)
+		}
+	case "vector":
+		var vector []struct {
+			Value prometheusSample `json:"value"`
+		}
+		if err := json.Unmarshal(data.Result, &vector); err != nil {
+			return 0, fmt.Errorf("invalid vector result: %w", err)
+		}
+		if len(vector) != 1 {
+			return 0, fmt.Errorf("the query returned %d samples instead of 1, aggregate it with sum or similar", len(vector))
+		}
+		sample = vector[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %s, the query has to return a scalar or vector", data.ResultType)
+	}
+	raw, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value: %v", sample[1])
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample value: %w", err)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("the query returned %s", raw)
+	}
+	return value, nil
+}
+
+// GetPrometheusReplicas calculates the replicas needed for the value of the query
+// It is the value divided by the target value per replica, rounded up
+func GetPrometheusReplicas(spec *v1alpha1.PrometheusAutoscalerSpec, value float64) (int32, error) {
+	if spec == nil {
+		return 0, errors.New("missing prometheus spec")
+	}
+	target := spec.TargetValuePerReplica.AsApproximateFloat64()
+	if target <= 0 {
+		return 0, fmt.Errorf("target value per replica must be positive: %s", spec.TargetValuePerReplica.String())
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("the query returned a negative value: %v", value)
+	}
+	// The small tolerance keeps float errors like 100.00000000000001 from adding a replica
+	replicas := math.Ceil(value/target - 1e-9)
+	if replicas > math.MaxInt32 {
+		return 0, fmt.Errorf("the query requires too many replicas: %v", replicas)
+	}
+	return int32(replicas), nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// newPrometheusServer starts a stand-in for the query api of prometheus, which answers every query with the body
+func newPrometheusServer(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" || r.FormValue("query") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"missing query"}`))
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+var _ = Describe("Prometheus Autoscale Testing", func() {
+	Context("When querying prometheus", func() {
+		ctx := context.Background()
+		query := func(status int, body string) (float64, error) {
+			server := newPrometheusServer(status, body)
+			defer server.Close()
+			return ProductionPrometheusQuery{}.Query(ctx, &v1alpha1.PrometheusAutoscalerSpec{ServerUrl: server.URL + "/", Query: "sum(players)"})
+		}
+
+		It("Reads vectors with a single sample", func() {
+			value, err := query(http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000.123,"42.5"]}]}}`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(Equal(42.5))
+		})
+
+		It("Reads scalars", func() {
+			value, err := query(http.StatusOK, `{"status":"success","data":{"resultType":"scalar","result":[1700000000.123,"7"]}}`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(Equal(7.0))
+		})
+
+		It("Fails on results without a single value", func() {
+			_, err := query(http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+			Expect(err).To(HaveOccurred())
+			_, err = query(http.StatusOK, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"a":"1"},"value":[1,"1"]},{"metric":{"a":"2"},"value":[1,"2"]}]}}`)
+			Expect(err).To(HaveOccurred())
+			_, err = query(http.StatusOK, `{"status":"success","data":{"resultType":"matrix","result":[]}}`)
+			Expect(err).To(HaveOccurred())
+			_, err = query(http.StatusOK, `{"status":"success","data":{"resultType":"scalar","result":[1,"NaN"]}}`)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Fails on query errors", func() {
+			_, err := query(http.StatusBadRequest, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("parse error"))
+			_, err = query(http.StatusBadGateway, `not json`)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When calculating the replicas", func() {
+		spec := func(target string) *v1alpha1.PrometheusAutoscalerSpec {
+			return &v1alpha1.PrometheusAutoscalerSpec{TargetValuePerReplica: resource.MustParse(target)}
+		}
+
+		It("Divides the value by the target per replica", func() {
+			replicas, err := GetPrometheusReplicas(spec("50"), 120)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(BeEquivalentTo(3))
+
+			replicas, err = GetPrometheusReplicas(spec("100m"), 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(BeEquivalentTo(100))
+
+			replicas, err = GetPrometheusReplicas(spec("50"), 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(BeEquivalentTo(0))
+		})
+
+		It("Fails on invalid targets and values", func() {
+			_, err := GetPrometheusReplicas(spec("0"), 10)
+			Expect(err).To(HaveOccurred())
+			_, err = GetPrometheusReplicas(spec("10"), -1)
+			Expect(err).To(HaveOccurred())
+			_, err = GetPrometheusReplicas(nil, 10)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	"github.com/robfig/cron/v3"
+)
+
+// scheduleLookback are the windows searched for the last start of a cron schedule, smallest first
+// so frequent schedules only need a few steps
+var scheduleLookback = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	31 * 24 * time.Hour,
+	366 * 24 * time.Hour,
+}
+
+// ScheduleResult is the outcome of evaluating a schedule policy at a point in time
+type ScheduleResult struct {
+	// If an entry, or the default replicas, decide the replicas
+	Active   bool
+	Replicas int32
+	// The name or cron of the entry that decided the replicas
+	Entry string
+	// The next time the result can change
+	Next time.Time
+}
+
+// GetScheduledReplicas evaluates the schedule at now.
+// The active entry that started most recently wins, on a tie the entry listed first wins.
+// Without an active entry, the default replicas are used if they are set.
+func GetScheduledReplicas(schedule *v1alpha1.ScheduleAutoscalerSpec, now time.Time) (ScheduleResult, error) {
+	if schedule == nil {
+		return ScheduleResult{}, fmt.Errorf("missing schedule spec")
+	}
+
+	var result ScheduleResult
+	var winnerStart time.Time
+	for _, entry := range schedule.Entries {
+		parsed, location, err := parseScheduleEntry(entry)
+		if err != nil {
+			return ScheduleResult{}, err
+		}
+		local := now.In(location)
+
+		if next := parsed.Next(local); !next.IsZero() && (result.Next.IsZero() || next.Before(result.Next)) {
+			result.Next = next
+		}
+
+		start, found := lastScheduleStart(parsed, local)
+		if !found {
+			continue
+		}
+		if entry.Duration != nil {
+			end := start.Add(entry.Duration.Duration)
+			if !end.After(local) {
+				continue
+			}
+			if result.Next.IsZero() || end.Before(result.Next) {
+				result.Next = end
+			}
+		}
+		if !result.Active || start.After(winnerStart) {
+			result.Active = true
+			result.Replicas = entry.Replicas
+			result.Entry = entry.Name
+			if result.Entry == "" {
+				result.Entry = entry.Cron
+			}
+			winnerStart = start
+		}
+	}
+
+	if !result.Active && schedule.DefaultReplicas != nil {
+		result.Active = true
+		result.Replicas = *schedule.DefaultReplicas
+		result.Entry = "default"
+	}
+	return result, nil
+}
+
+// parseScheduleEntry parses the cron expression and the time zone of a schedule entry
+func parseScheduleEntry(entry v1alpha1.ScheduleEntry) (cron.Schedule, *time.Location, error) {
+	location := time.UTC
+	if entry.TimeZone != nil {
+		loaded, err := time.LoadLocation(*entry.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone %s: %w", *entry.TimeZone, err)
+		}
+		location = loaded
+	}
+	parsed, err := cron.ParseStandard(entry.Cron)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cron expression %s: %w", entry.Cron, err)
+	}
+	return parsed, location, nil
+}
+
+// lastScheduleStart finds the last time the schedule started at or before now, looking back at most a year
+func lastScheduleStart(schedule cron.Schedule, now time.Time) (time.Time, bool) {
+	for _, window := range scheduleLookback {
+		var last time.Time
+		for start := schedule.Next(now.Add(-window)); !start.IsZero() && !start.After(now); start = schedule.Next(start) {
+			last = start
+		}
+		if !last.IsZero() {
+			return last, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Schedule Autoscaling", func() {
+	Context("When evaluating a schedule", func() {
+		// A wednesday
+		now := time.Date(2025, time.March, 12, 14, 30, 0, 0, time.UTC)
+		dayAndNight := &v1alpha1.ScheduleAutoscalerSpec{Entries: []v1alpha1.ScheduleEntry{
+			{Name: "day", Cron: "0 8 * * *", Replicas: 20},
+			{Name: "night", Cron: "0 22 * * *", Replicas: 5},
+		}}
+
+		It("Should use the entry that started most recently", func() {
+			result, err := GetScheduledReplicas(dayAndNight, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Active).To(BeTrue())
+			Expect(result.Replicas).To(Equal(int32(20)))
+			Expect(result.Entry).To(Equal("day"))
+			Expect(result.Next).To(Equal(time.Date(2025, time.March, 12, 22, 0, 0, 0, time.UTC)))
+
+			result, err = GetScheduledReplicas(dayAndNight, now.Add(10*time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Replicas).To(Equal(int32(5)))
+			Expect(result.Entry).To(Equal("night"))
+		})
+
+		It("Should prefer the entry listed first when they start together", func() {
+			schedule := &v1alpha1.ScheduleAutoscalerSpec{Entries: []v1alpha1.ScheduleEntry{
+				{Name: "wednesday", Cron: "0 8 * * 3", Replicas: 50},
+				{Name: "day", Cron: "0 8 * * *", Replicas: 20},
+			}}
+			result, err := GetScheduledReplicas(schedule, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Entry).To(Equal("wednesday"))
+		})
+
+		It("Should end entries after their duration and fall back to the default", func() {
+			defaultReplicas := int32(3)
+			schedule := &v1alpha1.ScheduleAutoscalerSpec{
+				Entries: []v1alpha1.ScheduleEntry{
+					{Name: "event", Cron: "0 12 * * *", Duration: &metav1.Duration{Duration: 2 * time.Hour}, Replicas: 40},
+				},
+				DefaultReplicas: &defaultReplicas,
+			}
+			result, err := GetScheduledReplicas(schedule, now.Add(-time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Replicas).To(Equal(int32(40)))
+			Expect(result.Next).To(Equal(time.Date(2025, time.March, 12, 14, 0, 0, 0, time.UTC)))
+
+			result, err = GetScheduledReplicas(schedule, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Active).To(BeTrue())
+			Expect(result.Replicas).To(Equal(int32(3)))
+
+			schedule.DefaultReplicas = nil
+			result, err = GetScheduledReplicas(schedule, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Active).To(BeFalse())
+		})
+
+		It("Should use the time zone of the entry", func() {
+			zone := "America/New_York"
+			schedule := &v1alpha1.ScheduleAutoscalerSpec{Entries: []v1alpha1.ScheduleEntry{
+				{Name: "morning", Cron: "0 10 * * *", TimeZone: &zone, Replicas: 10},
+				{Name: "utc-morning", Cron: "0 10 * * *", Replicas: 2},
+			}}
+			// 14:30 UTC is 10:30 in New York, so the New York entry started four hours after the UTC one
+			result, err := GetScheduledReplicas(schedule, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Entry).To(Equal("morning"))
+		})
+
+		It("Should fail on invalid entries", func() {
+			_, err := GetScheduledReplicas(nil, now)
+			Expect(err).To(HaveOccurred())
+			_, err = GetScheduledReplicas(&v1alpha1.ScheduleAutoscalerSpec{Entries: []v1alpha1.ScheduleEntry{{Cron: "not a cron"}}}, now)
+			Expect(err).To(HaveOccurred())
+			zone := "Nowhere/Land"
+			_, err = GetScheduledReplicas(&v1alpha1.ScheduleAutoscalerSpec{Entries: []v1alpha1.ScheduleEntry{{Cron: "* * * * *", TimeZone: &zone}}}, now)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
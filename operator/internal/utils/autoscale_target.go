@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ScaleTarget is the gametype or fleet an autoscaler scales
+type ScaleTarget struct {
+	Kind      v1alpha1.TargetKind
+	Name      string
+	Namespace string
+	// The game of the target, a fleet only has one when it belongs to a gametype
+	GameName string
+	// The fleet running the current server spec
+	FleetName string
+	Replicas  int32
+	// The labels every server of the target has
+	ServerLabels map[string]string
+	// The gametype or fleet itself, which is updated with the replicas
+	Object client.Object
+}
+
+// NewGameTypeTarget creates the target for a gametype, which includes the servers of all its fleets
+func NewGameTypeTarget(gametype *v1alpha1.GameType) *ScaleTarget {
+	return &ScaleTarget{
+		Kind:         v1alpha1.GameTypeTarget,
+		Name:         gametype.Name,
+		Namespace:    gametype.Namespace,
+		GameName:     gametype.Name,
+		FleetName:    gametype.Status.CurrentFleetName,
+		Replicas:     gametype.Spec.FleetSpec.Scaling.Replicas,
+		ServerLabels: map[string]string{"gametype": gametype.Name},
+		Object:       gametype,
+	}
+}
+
+// NewFleetTarget creates the target for a fleet
+func NewFleetTarget(fleet *v1alpha1.Fleet) *ScaleTarget {
+	return &ScaleTarget{
+		Kind:         v1alpha1.FleetTarget,
+		Name:         fleet.Name,
+		Namespace:    fleet.Namespace,
+		GameName:     fleet.Labels["gametype"],
+		FleetName:    fleet.Name,
+		Replicas:     fleet.Spec.Scaling.Replicas,
+		ServerLabels: map[string]string{"fleet": fleet.Name},
+		Object:       fleet,
+	}
+}
+
+// GetFleetGameType returns the name of the gametype that manages the fleet, or an empty string for a standalone fleet
+// A fleet is managed when it has the gametype label, or a gametype as owner
+func GetFleetGameType(fleet *v1alpha1.Fleet) string {
+	if name := fleet.Labels["gametype"]; name != "" {
+		return name
+	}
+	for _, owner := range fleet.OwnerReferences {
+		if owner.Kind == "GameType" && owner.APIVersion == v1alpha1.GroupVersion.String() {
+			return owner.Name
+		}
+	}
+	return ""
+}
+
+// GetScaleTarget gets the target of the autoscaler from the cluster
+func GetScaleTarget(ctx context.Context, c client.Client, autoscaler *v1alpha1.GameTypeAutoscaler) (*ScaleTarget, error) {
+	target := autoscaler.Spec.GetTarget()
+	namespacedName := types.NamespacedName{Name: target.Name, Namespace: autoscaler.Namespace}
+	switch target.Kind {
+	case v1alpha1.GameTypeTarget:
+		gametype := &v1alpha1.GameType{}
+		if err := c.Get(ctx, namespacedName, gametype); err != nil {
+			return nil, err
+		}
+		return NewGameTypeTarget(gametype), nil
+	case v1alpha1.FleetTarget:
+		fleet := &v1alpha1.Fleet{}
+		if err := c.Get(ctx, namespacedName, fleet); err != nil {
+			return nil, err
+		}
+		return NewFleetTarget(fleet), nil
+	default:
+		return nil, fmt.Errorf("%s is not a supported target kind", target.Kind)
+	}
+}
+
+// SetReplicas sets the replicas on the object of the target, the caller still has to update it
+func (t *ScaleTarget) SetReplicas(replicas int32) {
+	t.Replicas = replicas
+	switch object := t.Object.(type) {
+	case *v1alpha1.GameType:
+		object.Spec.FleetSpec.Scaling.Replicas = replicas
+	case *v1alpha1.Fleet:
+		object.Spec.Scaling.Replicas = replicas
+	}
+}
+
+// getTargetServers lists the servers of the target
+func getTargetServers(ctx context.Context, c client.Client, target *ScaleTarget) (*v1alpha1.ServerList, error) {
+	servers := &v1alpha1.ServerList{}
+	if err := c.List(ctx, servers, client.InNamespace(target.Namespace), client.MatchingLabels(target.ServerLabels)); err != nil {
+		return nil, fmt.Errorf("failed to list the servers of the %s: %w", target.Kind, err)
+	}
+	return servers, nil
+}
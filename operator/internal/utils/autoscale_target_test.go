@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Autoscale Target Testing", func() {
+	Context("When getting the target of an autoscaler", func() {
+		ctx := context.Background()
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		gametype := &v1alpha1.GameType{
+			ObjectMeta: metav1.ObjectMeta{Name: "game", Namespace: "default"},
+			Spec:       v1alpha1.GameTypeSpec{FleetSpec: v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{Replicas: 2}}},
+			Status:     v1alpha1.GameTypeStatus{CurrentFleetName: "game-fleet"},
+		}
+		fleet := &v1alpha1.Fleet{
+			ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "default"},
+			Spec:       v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{Replicas: 4}},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gametype, fleet).Build()
+
+		It("Falls back to the gametype name", func() {
+			autoscaler := &v1alpha1.GameTypeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "default"},
+				Spec:       v1alpha1.GameTypeAutoscalerSpec{GameTypeName: "game"},
+			}
+			target, err := GetScaleTarget(ctx, c, autoscaler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Kind).To(Equal(v1alpha1.GameTypeTarget))
+			Expect(target.FleetName).To(Equal("game-fleet"))
+			Expect(target.Replicas).To(BeEquivalentTo(2))
+			Expect(target.ServerLabels).To(Equal(map[string]string{"gametype": "game"}))
+
+			target.SetReplicas(5)
+			Expect(target.Object.(*v1alpha1.GameType).Spec.FleetSpec.Scaling.Replicas).To(BeEquivalentTo(5))
+		})
+
+		It("Targets a fleet", func() {
+			autoscaler := &v1alpha1.GameTypeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "default"},
+				Spec: v1alpha1.GameTypeAutoscalerSpec{
+					GameTypeName: "game",
+					TargetRef:    &v1alpha1.TargetRef{Kind: v1alpha1.FleetTarget, Name: "standalone"},
+				},
+			}
+			target, err := GetScaleTarget(ctx, c, autoscaler)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.Kind).To(Equal(v1alpha1.FleetTarget))
+			Expect(target.GameName).To(BeEmpty())
+			Expect(target.Replicas).To(BeEquivalentTo(4))
+			Expect(target.ServerLabels).To(Equal(map[string]string{"fleet": "standalone"}))
+
+			target.SetReplicas(1)
+			Expect(target.Object.(*v1alpha1.Fleet).Spec.Scaling.Replicas).To(BeEquivalentTo(1))
+		})
+
+		It("Finds the gametype that manages a fleet", func() {
+			Expect(GetFleetGameType(fleet)).To(BeEmpty())
+
+			labeled := fleet.DeepCopy()
+			labeled.Labels = map[string]string{"gametype": "game"}
+			Expect(GetFleetGameType(labeled)).To(Equal("game"))
+
+			owned := fleet.DeepCopy()
+			owned.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       "GameType",
+				Name:       "owner",
+			}}
+			Expect(GetFleetGameType(owned)).To(Equal("owner"))
+		})
+
+		It("Fails on unknown targets", func() {
+			autoscaler := &v1alpha1.GameTypeAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "default"},
+				Spec:       v1alpha1.GameTypeAutoscalerSpec{TargetRef: &v1alpha1.TargetRef{Kind: "Deployment", Name: "game"}},
+			}
+			_, err := GetScaleTarget(ctx, c, autoscaler)
+			Expect(err).To(HaveOccurred())
+
+			autoscaler.Spec.TargetRef = &v1alpha1.TargetRef{Kind: v1alpha1.FleetTarget, Name: "missing"}
+			_, err = GetScaleTarget(ctx, c, autoscaler)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -9,21 +9,38 @@ const (
 	ReasonServerPodDeleted         EventReason = "ServerPodDeleted"
 	ReasonServerPodCreationFailed  EventReason = "ServerPodCreationFailed"
 	ReasonServerUpdateFAiled       EventReason = "ServerUpdateFailed"
+	ReasonServerStateChanged       EventReason = "ServerStateChanged"
+	ReasonServerCrashed            EventReason = "ServerCrashed"
+	ReasonServerUnhealthy          EventReason = "ServerUnhealthy"
 
 	ReasonFleetInitialized    EventReason = "FleetInitialized"
 	ReasonFleetUpdateFailed   EventReason = "FleetUpdateFailed"
 	ReasonFleetServersRemoved EventReason = "FleetServersRemoved"
 	ReasonFleetScaleServers   EventReason = "FleetScaleServers"
+	ReasonFleetServerFailed   EventReason = "FleetServerFailed"
 
 	ReasonGametypeInitialized     EventReason = "GametypeInitialized"
 	ReasonGameTypeDeleting        EventReason = "GameTypeDeleting"
 	ReasonGametypeServersDeleted  EventReason = "GametypeServersDeleted"
 	ReasonGametypeSpecUpdated     EventReason = "GametypeSpecUpdated"
 	ReasonGametypeReplicasUpdated EventReason = "GametypeReplicasUpdated"
+	ReasonGametypeCanary          EventReason = "GametypeCanary"
+	ReasonGametypeRollback        EventReason = "GametypeRollback"
 
 	ReasonGameTypeAutoscalerInvalidServer          EventReason = "GameAutoscalerInvalidServer"
 	ReasonGameTypeAutoscalerInvalidAutoscalePolicy EventReason = "GameautoscalerInvalidAutoscalePolicy"
 	ReasonGameTypeAutoscalerInvalidSyncType        EventReason = "GameautoscalerInvalidSyncType"
 	ReasonGameTypeAutoscalerWebhook                EventReason = "GameautoscalerWebhook"
 	ReasonGameTypeAutoscalerScale                  EventReason = "GameautoscalerScale"
+	ReasonGameTypeAutoscalerBuffer                 EventReason = "GameautoscalerBuffer"
+	ReasonGameTypeAutoscalerSchedule               EventReason = "GameautoscalerSchedule"
+	ReasonGameTypeAutoscalerClamped                EventReason = "GameautoscalerClamped"
+	ReasonGameTypeAutoscalerPrometheus             EventReason = "GameautoscalerPrometheus"
+	ReasonGameTypeAutoscalerGrpc                   EventReason = "GameautoscalerGrpc"
+	ReasonGameTypeAutoscalerStabilized             EventReason = "GameautoscalerStabilized"
+	ReasonGameTypeAutoscalerInvalidTarget          EventReason = "GameautoscalerInvalidTarget"
+
+	ReasonServerAllocationAllocated   EventReason = "ServerAllocationAllocated"
+	ReasonServerAllocationUnAllocated EventReason = "ServerAllocationUnAllocated"
+	ReasonServerAllocationInvalid     EventReason = "ServerAllocationInvalid"
 )
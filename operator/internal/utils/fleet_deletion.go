@@ -6,10 +6,9 @@ import (
 	"fmt"
 	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"strconv"
+	"sort"
 )
 
 type FleetDeletionChecker interface {
@@ -17,122 +16,65 @@ type FleetDeletionChecker interface {
 }
 
 // FindDeleteServer is used to find the server that should be deleted.
-// It is based on the specs agepriority field.
+// It is based on the specs agepriority field. Allocated servers are never chosen.
 func FindDeleteServer(ctx context.Context, fleet *v1alpha1.Fleet, servers *v1alpha1.ServerList, client client.Client, checker FleetDeletionChecker) (*v1alpha1.Server, error) {
-	strategy := fleet.Spec.Scaling.AgePriority
-	deleteFirst := fleet.Spec.Scaling.PrioritizeAllowed
-
-	if strategy == v1alpha1.OldestFirst {
-		return getOldestServer(ctx, servers, deleteFirst, &client, checker)
+	toDelete, err := FindDeleteServers(ctx, fleet, servers, client, checker, 1)
+	if err != nil {
+		return nil, err
 	}
-
-	if strategy == v1alpha1.NewestFirst {
-		return getNewestServer(ctx, servers, deleteFirst, &client, checker)
-
+	if len(toDelete) == 0 {
+		return nil, fmt.Errorf("no servers found")
 	}
-	return nil, fmt.Errorf("invalid scaling strategy: %s", strategy)
+	return toDelete[0], nil
 }
 
-// getOldestServer gets the server of the fleet, that is the oldest
-// If deleteFirst is enabled, then it tries to get the server that can be deleted, but that is the oldest out of those.
-// If it cannot find any where deletion is allowed, it returns the oldest server.
-func getOldestServer(ctx context.Context, servers *v1alpha1.ServerList, deleteFirst bool, client *client.Client, checker FleetDeletionChecker) (*v1alpha1.Server, error) {
-	var oldestServer *v1alpha1.Server
-	var oldestTime *metav1.Time
-	var oldestAllowedServer *v1alpha1.Server
-	var oldestAllowTime *metav1.Time
+// FindDeleteServers is used to find up to count servers that should be deleted, in the order they should be deleted.
+// Servers where deletion is allowed come first when the fleet prioritizes them, then the servers are ordered by the agepriority field.
+// Servers that are allocated or already terminating are skipped.
+// The sidecar is asked at most once per server, so this can be used for large scale downs.
+func FindDeleteServers(ctx context.Context, fleet *v1alpha1.Fleet, servers *v1alpha1.ServerList, client client.Client, checker FleetDeletionChecker, count int) ([]*v1alpha1.Server, error) {
+	strategy := fleet.Spec.Scaling.AgePriority
+	if strategy != v1alpha1.OldestFirst && strategy != v1alpha1.NewestFirst {
+		return nil, fmt.Errorf("invalid scaling strategy: %s", strategy)
+	}
 
-	//Go over all servers
+	var candidates []*v1alpha1.Server
+	allowed := map[*v1alpha1.Server]bool{}
 	for i := range servers.Items {
 		server := &servers.Items[i]
-		//If time is null, or this server was created before current oldest
-		if oldestTime == nil || server.CreationTimestamp.Before(oldestTime) {
-			//Set new oldest
-			oldestTime = &server.CreationTimestamp
-			oldestServer = server
+		if server.Status.State == v1alpha1.ServerStateAllocated || server.DeletionTimestamp != nil {
+			continue
 		}
-		// Check if we want to prioritize allowed
-		if deleteFirst {
-			//Check if this is allowed
-			allowed, err := checker.isDeleteAllowed(ctx, server, client)
+		candidates = append(candidates, server)
+		if fleet.Spec.Scaling.PrioritizeAllowed {
+			isAllowed, err := checker.isDeleteAllowed(ctx, server, &client)
 			if err != nil {
 				return nil, err
 			}
-			if allowed {
-				//If it is allowed, check current oldest allowed
-				if oldestAllowTime == nil || server.CreationTimestamp.Before(oldestAllowTime) {
-					//Update to new oldest allowed
-					oldestAllowTime = &server.CreationTimestamp
-					oldestAllowedServer = server
-				}
-			}
+			allowed[server] = isAllowed
 		}
 	}
 
-	if oldestServer == nil {
-		return nil, fmt.Errorf("no servers found")
-	}
-
-	//If this is not nil, it means we found an oldest server and the boolean was true
-	if oldestAllowedServer != nil {
-		return oldestAllowedServer, nil
-	}
-
-	//If above was nil, return this instead
-	return oldestServer, nil
-}
-
-// getNewestServer gets the server of the fleet, that is the newest
-// If deleteFirst is enabled, then it tries to get the server that can be deleted, but that is the youngest out of those.
-// If it cannot find any where deletion is allowed, it returns the youngest server.
-func getNewestServer(ctx context.Context, servers *v1alpha1.ServerList, deleteFirst bool, client *client.Client, checker FleetDeletionChecker) (*v1alpha1.Server, error) {
-
-	var newestServer *v1alpha1.Server
-	var newestTime *metav1.Time
-	var newestAllowedServer *v1alpha1.Server
-	var newestAllowTime *metav1.Time
-
-	// Go over all of the servers
-	for i := range servers.Items {
-		server := &servers.Items[i]
-		//If time is null, or this server was created after current youngest
-		if newestTime == nil || server.CreationTimestamp.After(newestTime.Time) {
-			// Update with new youngest
-			newestTime = &server.CreationTimestamp
-			newestServer = server
+	sort.SliceStable(candidates, func(i, j int) bool {
+		first, second := candidates[i], candidates[j]
+		if allowed[first] != allowed[second] {
+			return allowed[first]
 		}
-		// Check if we want to prioritize allowed
-		if deleteFirst {
-			//Check if this is allowed
-			allowed, err := checker.isDeleteAllowed(ctx, server, client)
-			if err != nil {
-				return nil, err
-			}
-			if allowed {
-				// If it is, we want to check if there already is a prioritized time, and is it after the current iterations one
-				if newestAllowTime == nil || server.CreationTimestamp.After(newestAllowTime.Time) {
-					newestAllowTime = &server.CreationTimestamp
-					newestAllowedServer = server
-				}
-			}
+		if strategy == v1alpha1.NewestFirst {
+			return first.CreationTimestamp.After(second.CreationTimestamp.Time)
 		}
-	}
+		return first.CreationTimestamp.Before(&second.CreationTimestamp)
+	})
 
-	if newestServer == nil {
-		return nil, fmt.Errorf("no servers found")
+	if len(candidates) > count {
+		candidates = candidates[:count]
 	}
-
-	//If this is not nil, it means we found a youngest server and the boolean was true
-	if newestAllowedServer != nil {
-		return newestAllowedServer, nil
-	}
-
-	//If above was nil, return this instead
-	return newestServer, nil
+	return candidates, nil
 }
 
 // isDeleteAllowed is a utility for a server object, to communicate with the sidecar to see if deletion is allowed
-func (ProdDeletionChecker) isDeleteAllowed(ctx context.Context, server *v1alpha1.Server, c *client.Client) (bool, error) {
+// In push mode the sidecar is not asked, and the state it pushed to the pod is used instead
+func (p ProdDeletionChecker) isDeleteAllowed(ctx context.Context, server *v1alpha1.Server, c *client.Client) (bool, error) {
 	podName := server.Name + "-pod"
 	pod := &v1.Pod{}
 	err := (*c).Get(ctx, types.NamespacedName{Namespace: server.Namespace, Name: podName}, pod)
@@ -140,8 +82,7 @@ func (ProdDeletionChecker) isDeleteAllowed(ctx context.Context, server *v1alpha1
 		return false, err
 	}
 
-	port := strconv.Itoa(*server.Spec.SidecarSettings.Port)
-	allowed, err := IsDeleteAllowed(pod, port)
+	allowed, err := p.IsGameDeleteAllowed(server, pod)
 	if err != nil {
 		return false, nil
 	}
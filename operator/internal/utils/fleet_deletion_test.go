@@ -18,6 +18,10 @@ func (f FakeFleetDeleteChecker) isDeleteAllowed(ctx context.Context, server *v1a
 	return f.DeletionState[server.Name], nil
 }
 
+func deletionFleet(agePriority v1alpha1.Priority, prioritizeAllowed bool) *v1alpha1.Fleet {
+	return &v1alpha1.Fleet{Spec: v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{AgePriority: agePriority, PrioritizeAllowed: prioritizeAllowed}}}
+}
+
 var _ = Describe("Fleet Utility Testing", func() {
 	Context("When finding the server to delete", func() {
 		ctx := context.Background()
@@ -46,7 +50,7 @@ var _ = Describe("Fleet Utility Testing", func() {
 				},
 			}}
 			By("Find the oldest server")
-			server, err := getOldestServer(ctx, &servers, false, nil, fake)
+			server, err := FindDeleteServer(ctx, deletionFleet(v1alpha1.OldestFirst, false), &servers, nil, fake)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(servers.Items).To(HaveLen(3))
 			Expect(server.Name).To(Equal("server1"))
@@ -79,7 +83,7 @@ var _ = Describe("Fleet Utility Testing", func() {
 			By("Find oldest deletable server")
 			fake.DeletionState["server2"] = true
 			fake.DeletionState["server3"] = true
-			server, err := getOldestServer(ctx, &servers, true, nil, fake)
+			server, err := FindDeleteServer(ctx, deletionFleet(v1alpha1.OldestFirst, true), &servers, nil, fake)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(servers.Items).To(HaveLen(3))
 			Expect(server.Name).To(Equal("server3"))
@@ -110,7 +114,7 @@ var _ = Describe("Fleet Utility Testing", func() {
 				},
 			}}
 			By("Find the oldest server")
-			server, err := getNewestServer(ctx, &servers, false, nil, fake)
+			server, err := FindDeleteServer(ctx, deletionFleet(v1alpha1.NewestFirst, false), &servers, nil, fake)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(servers.Items).To(HaveLen(3))
 			Expect(server.Name).To(Equal("server2"))
@@ -143,10 +147,107 @@ var _ = Describe("Fleet Utility Testing", func() {
 			By("Find youngest deletable server")
 			fake.DeletionState["server2"] = true
 			fake.DeletionState["server3"] = true
-			server, err := getOldestServer(ctx, &servers, true, nil, fake)
+			server, err := FindDeleteServer(ctx, deletionFleet(v1alpha1.OldestFirst, true), &servers, nil, fake)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(servers.Items).To(HaveLen(3))
 			Expect(server.Name).To(Equal("server3"))
 		})
+
+		It("Never picks allocated servers", func() {
+			By("Setup objects")
+			baseTime := time.Now()
+			fake := FakeFleetDeleteChecker{DeletionState: make(map[string]bool)}
+			servers := v1alpha1.ServerList{Items: []v1alpha1.Server{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "server1",
+						CreationTimestamp: metav1.Time{Time: baseTime},
+					},
+					Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateAllocated},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "server2",
+						CreationTimestamp: metav1.Time{Time: baseTime.Add(time.Hour)},
+					},
+					Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateAllocated},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "server3",
+						CreationTimestamp: metav1.Time{Time: baseTime.Add(time.Minute)},
+					},
+					Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateReady},
+				},
+			}}
+			fake.DeletionState["server1"] = true
+			fake.DeletionState["server2"] = true
+
+			By("Find the oldest server")
+			server, err := FindDeleteServer(ctx, deletionFleet(v1alpha1.OldestFirst, true), &servers, nil, fake)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Name).To(Equal("server3"))
+
+			By("Find the youngest server")
+			server, err = FindDeleteServer(ctx, deletionFleet(v1alpha1.NewestFirst, true), &servers, nil, fake)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(server.Name).To(Equal("server3"))
+
+			By("Fail when every server is allocated")
+			servers.Items[2].Status.State = v1alpha1.ServerStateAllocated
+			_, err = FindDeleteServer(ctx, deletionFleet(v1alpha1.OldestFirst, false), &servers, nil, fake)
+			Expect(err).To(HaveOccurred())
+			_, err = FindDeleteServer(ctx, deletionFleet(v1alpha1.NewestFirst, false), &servers, nil, fake)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("When finding several servers to delete", func() {
+		ctx := context.Background()
+		baseTime := time.Now()
+		deletionTime := metav1.Now()
+		newServers := func() *v1alpha1.ServerList {
+			return &v1alpha1.ServerList{Items: []v1alpha1.Server{
+				{ObjectMeta: metav1.ObjectMeta{Name: "server1", CreationTimestamp: metav1.Time{Time: baseTime}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "server2", CreationTimestamp: metav1.Time{Time: baseTime.Add(time.Hour)}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "server3", CreationTimestamp: metav1.Time{Time: baseTime.Add(time.Minute)}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "allocated", CreationTimestamp: metav1.Time{Time: baseTime}}, Status: v1alpha1.ServerStatus{State: v1alpha1.ServerStateAllocated}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "terminating", CreationTimestamp: metav1.Time{Time: baseTime}, DeletionTimestamp: &deletionTime}},
+			}}
+		}
+		names := func(servers []*v1alpha1.Server) []string {
+			var result []string
+			for _, server := range servers {
+				result = append(result, server.Name)
+			}
+			return result
+		}
+
+		It("Orders the servers by age", func() {
+			fake := FakeFleetDeleteChecker{DeletionState: make(map[string]bool)}
+			fleet := &v1alpha1.Fleet{Spec: v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{AgePriority: v1alpha1.OldestFirst}}}
+			servers, err := FindDeleteServers(ctx, fleet, newServers(), nil, fake, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(servers)).To(Equal([]string{"server1", "server3"}))
+
+			fleet.Spec.Scaling.AgePriority = v1alpha1.NewestFirst
+			servers, err = FindDeleteServers(ctx, fleet, newServers(), nil, fake, 10)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(servers)).To(Equal([]string{"server2", "server3", "server1"}))
+		})
+
+		It("Puts servers where deletion is allowed first", func() {
+			fake := FakeFleetDeleteChecker{DeletionState: map[string]bool{"server2": true}}
+			fleet := &v1alpha1.Fleet{Spec: v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{AgePriority: v1alpha1.OldestFirst, PrioritizeAllowed: true}}}
+			servers, err := FindDeleteServers(ctx, fleet, newServers(), nil, fake, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(servers)).To(Equal([]string{"server2", "server1"}))
+		})
+
+		It("Fails on an invalid strategy", func() {
+			fake := FakeFleetDeleteChecker{DeletionState: make(map[string]bool)}
+			fleet := &v1alpha1.Fleet{Spec: v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{AgePriority: "smallest_first"}}}
+			_, err := FindDeleteServers(ctx, fleet, newServers(), nil, fake, 1)
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
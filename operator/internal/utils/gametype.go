@@ -2,9 +2,18 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
 	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -44,3 +53,129 @@ func GetFleetObjectForType(gametype *v1alpha1.GameType) *v1alpha1.Fleet {
 
 	return fleet
 }
+
+// GetFleetsForUpdate splits the fleets of a gametype into the fleet matching its spec, and the old fleets that have to be replaced.
+// If several fleets match, the newest one is used. Fleets that are being deleted are skipped and old fleets are sorted oldest first.
+func GetFleetsForUpdate(gametype *v1alpha1.GameType, fleets *v1alpha1.FleetList) (*v1alpha1.Fleet, []v1alpha1.Fleet) {
+	var active []v1alpha1.Fleet
+	for _, fleet := range fleets.Items {
+		if fleet.DeletionTimestamp == nil {
+			active = append(active, fleet)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].CreationTimestamp.Before(&active[j].CreationTimestamp)
+	})
+
+	current := -1
+	for i := range active {
+		if v1alpha1.AreFleetsPodsEqual(&active[i].Spec, &gametype.Spec.FleetSpec) {
+			current = i
+		}
+	}
+	if current == -1 {
+		return nil, active
+	}
+	currentFleet := active[current]
+	return &currentFleet, append(active[:current:current], active[current+1:]...)
+}
+
+var defaultRevisionHistoryLimit int32 = 5
+
+// maxRevisionHistoryLimit caps the revisions kept, in case the gametype was created before the limit was validated
+var maxRevisionHistoryLimit int32 = 50
+
+// GetRevisionHash returns the hash of a server spec, which names the ControllerRevision storing it
+func GetRevisionHash(spec *v1alpha1.ServerSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode server spec: %w", err)
+	}
+	hasher := fnv.New64a()
+	hasher.Write(data)
+	return rand.SafeEncodeString(strconv.FormatUint(hasher.Sum64(), 10)), nil
+}
+
+// GetRevisionName returns the name of the ControllerRevision storing the server spec with the given hash
+func GetRevisionName(gametype *v1alpha1.GameType, hash string) string {
+	return gametype.Name + "-" + hash
+}
+
+// GetControllerRevision returns the ControllerRevision storing the server spec of a new fleet of the gametype.
+// It is owned by the gametype, so it is removed with it.
+func GetControllerRevision(gametype *v1alpha1.GameType, spec *v1alpha1.ServerSpec, hash string) (*appsv1.ControllerRevision, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode server spec: %w", err)
+	}
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetRevisionName(gametype, hash),
+			Namespace: gametype.Namespace,
+			Labels:    map[string]string{"gametype": gametype.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(gametype, v1alpha1.GroupVersion.WithKind("GameType")),
+			},
+		},
+		Data: runtime.RawExtension{Raw: data},
+	}, nil
+}
+
+// GetRevisionServerSpec returns the server spec of a revision, or nil if its ControllerRevision does not exist anymore
+func GetRevisionServerSpec(ctx context.Context, c client.Client, gametype *v1alpha1.GameType, revision *v1alpha1.GameTypeRevision) (*v1alpha1.ServerSpec, error) {
+	controllerRevision := &appsv1.ControllerRevision{}
+	key := client.ObjectKey{Namespace: gametype.Namespace, Name: GetRevisionName(gametype, revision.Hash)}
+	if err := c.Get(ctx, key, controllerRevision); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	spec := &v1alpha1.ServerSpec{}
+	if err := json.Unmarshal(controllerRevision.Data.Raw, spec); err != nil {
+		return nil, fmt.Errorf("failed to decode server spec of revision %d: %w", revision.Revision, err)
+	}
+	return spec, nil
+}
+
+// AddRevision records the server spec of a newly created fleet as the newest revision of the gametype.
+// A revision with the same server spec is replaced, so rolling back gets a new revision number like in deployments.
+// The oldest revisions are dropped once there are more than the revision history limit, and returned so their
+// ControllerRevisions can be deleted.
+func AddRevision(gametype *v1alpha1.GameType, fleet *v1alpha1.Fleet, hash string, now metav1.Time) []v1alpha1.GameTypeRevision {
+	var last int64
+	revisions := make([]v1alpha1.GameTypeRevision, 0, len(gametype.Status.Revisions)+1)
+	for _, revision := range gametype.Status.Revisions {
+		last = max(last, revision.Revision)
+		if revision.Hash == hash {
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+	revisions = append(revisions, v1alpha1.GameTypeRevision{
+		Revision:  last + 1,
+		FleetName: fleet.Name,
+		CreatedAt: now,
+		Hash:      hash,
+	})
+
+	limit := defaultRevisionHistoryLimit
+	if gametype.Spec.RevisionHistoryLimit != nil {
+		limit = min(max(1, *gametype.Spec.RevisionHistoryLimit), maxRevisionHistoryLimit)
+	}
+	var dropped []v1alpha1.GameTypeRevision
+	if len(revisions) > int(limit) {
+		dropped = revisions[:len(revisions)-int(limit)]
+		revisions = revisions[len(revisions)-int(limit):]
+	}
+	gametype.Status.Revisions = revisions
+	gametype.Status.CurrentRevision = last + 1
+	return dropped
+}
+
+// FindRevision returns the revision of the gametype with the given number, or nil if it is not kept anymore
+func FindRevision(gametype *v1alpha1.GameType, revision int64) *v1alpha1.GameTypeRevision {
+	for i := range gametype.Status.Revisions {
+		if gametype.Status.Revisions[i].Revision == revision {
+			return &gametype.Status.Revisions[i]
+		}
+	}
+	return nil
+}
@@ -1,13 +1,57 @@
 package utils
 
 import (
+	"fmt"
 	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"math"
 	"os"
 	"strconv"
+	"time"
 )
 
+// SIDECAR_STATE_VOLUME is the emptyDir the sidecar persists its state to, so the state survives restarts of the sidecar container
+const SIDECAR_STATE_VOLUME = "fallernetes-sidecar-state"
+
+// SIDECAR_STATE_PATH is where the state volume is mounted in the sidecar container
+const SIDECAR_STATE_PATH = "/var/run/fallernetes"
+
+// SIDECAR_TOKEN_VOLUME holds the service account token of the sidecar in push mode, which is not mounted into the game containers
+const SIDECAR_TOKEN_VOLUME = "fallernetes-sidecar-token"
+
+// SIDECAR_TOKEN_PATH is where the sidecar expects the token, the same path kubernetes mounts it to
+const SIDECAR_TOKEN_PATH = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// DEFAULT_SIDECAR_PORT is the port of the sidecar, when the server does not set one
+const DEFAULT_SIDECAR_PORT = 8080
+
+// DEFAULT_SIDECAR_IMAGE is the image of the sidecar, when the server does not set one
+const DEFAULT_SIDECAR_IMAGE = "unfamousthomas/fallernetes-sidecar:main"
+
+// GetSidecarSettings returns the sidecar settings of the server, with the defaults for the settings it does not set
+func GetSidecarSettings(server *v1alpha1.Server) v1alpha1.SidecarSettings {
+	var settings v1alpha1.SidecarSettings
+	if server.Spec.SidecarSettings != nil {
+		settings = *server.Spec.SidecarSettings
+	}
+	if settings.Port == nil {
+		port := DEFAULT_SIDECAR_PORT
+		settings.Port = &port
+	}
+	if settings.SidecarImage == nil {
+		image := DEFAULT_SIDECAR_IMAGE
+		settings.SidecarImage = &image
+	}
+	return settings
+}
+
+// getSidecarPort returns the port of the sidecar of the server, as used in its url
+func getSidecarPort(server *v1alpha1.Server) string {
+	return strconv.Itoa(*GetSidecarSettings(server).Port)
+}
+
 func addContainer(spec *corev1.PodSpec, container corev1.Container) *corev1.PodSpec {
 	spec.Containers = append(spec.Containers, container)
 	return spec
@@ -15,7 +59,7 @@ func addContainer(spec *corev1.PodSpec, container corev1.Container) *corev1.PodS
 
 func getPodSpec(server *v1alpha1.Server) *corev1.PodSpec {
 	spec := server.Spec
-	sidecarSettings := spec.SidecarSettings
+	sidecarSettings := GetSidecarSettings(server)
 	portStr := strconv.Itoa(*sidecarSettings.Port)
 	debugStr := strconv.FormatBool(sidecarSettings.LogDebug)
 
@@ -37,9 +81,77 @@ func getPodSpec(server *v1alpha1.Server) *corev1.PodSpec {
 				Name:  "DEBUG",
 				Value: debugStr,
 			},
+			{
+				Name:  "STATE_FILE",
+				Value: SIDECAR_STATE_PATH + "/state.json",
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      SIDECAR_STATE_VOLUME,
+				MountPath: SIDECAR_STATE_PATH,
+			},
 		},
 		ImagePullPolicy: corev1.PullIfNotPresent,
 	})
+	if sidecarSettings.Mode == v1alpha1.SidecarModePush {
+		sidecar := &pod.Containers[len(pod.Containers)-1]
+		sidecar.Env = append(sidecar.Env,
+			corev1.EnvVar{
+				Name:  "SIDECAR_MODE",
+				Value: string(v1alpha1.SidecarModePush),
+			},
+			corev1.EnvVar{
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
+			},
+			corev1.EnvVar{
+				Name: "POD_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.namespace",
+					},
+				},
+			},
+		)
+		// Pods with their own service account have to be allowed to patch pods by the user
+		// Otherwise only the sidecar gets the token, so the game can not patch its pod
+		if pod.ServiceAccountName == "" {
+			pod.ServiceAccountName = GetSidecarServiceAccountName(server)
+			automount := false
+			pod.AutomountServiceAccountToken = &automount
+			sidecar.VolumeMounts = append(sidecar.VolumeMounts, corev1.VolumeMount{
+				Name:      SIDECAR_TOKEN_VOLUME,
+				MountPath: SIDECAR_TOKEN_PATH,
+				ReadOnly:  true,
+			})
+			pod.Volumes = append(pod.Volumes, getSidecarTokenVolume())
+		}
+	}
+	pod.Volumes = append(pod.Volumes, corev1.Volume{
+		Name:         SIDECAR_STATE_VOLUME,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	if sidecarSettings.HeartbeatDeadline != nil {
+		sidecar := &pod.Containers[len(pod.Containers)-1]
+		sidecar.Env = append(sidecar.Env, corev1.EnvVar{
+			Name:  "HEARTBEAT_DEADLINE",
+			Value: sidecarSettings.HeartbeatDeadline.Duration.String(),
+		})
+		// The kubelet restarts a hung game in place, as the heartbeat endpoint of the sidecar fails once the deadline is missed
+		if spec.RestartPolicy == "" || spec.RestartPolicy == v1alpha1.ServerRestartInPlace {
+			probe := getHeartbeatProbe(*sidecarSettings.Port, sidecarSettings.HeartbeatDeadline.Duration)
+			for i := range pod.Containers[:len(pod.Containers)-1] {
+				if pod.Containers[i].LivenessProbe == nil {
+					pod.Containers[i].LivenessProbe = probe.DeepCopy()
+				}
+			}
+		}
+	}
 
 	for i := range pod.Containers {
 		container := &pod.Containers[i]
@@ -51,6 +163,11 @@ func getPodSpec(server *v1alpha1.Server) *corev1.PodSpec {
 			Name:  "SERVER_NAME",
 			Value: server.Name,
 		})
+		// Used by the sdk of the sidecar to find the sidecar
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "SIDECAR_PORT",
+			Value: portStr,
+		})
 		if fleet, ok := server.Labels["fleet"]; ok {
 			container.Env = append(container.Env, corev1.EnvVar{
 				Name:  "FLEET_NAME",
@@ -94,9 +211,50 @@ func getPodSpec(server *v1alpha1.Server) *corev1.PodSpec {
 		Name: os.Getenv("IMAGE_PULL_SECRET_NAME"),
 	})
 
+	// The operator handles crashes itself, so the kubelet must not restart the containers
+	if spec.RestartPolicy == v1alpha1.ServerRestartReplace || spec.RestartPolicy == v1alpha1.ServerRestartFail {
+		pod.RestartPolicy = corev1.RestartPolicyNever
+	}
+
 	return pod
 }
 
+// getSidecarTokenVolume projects the token and the ca of the api server, like kubernetes does when automounting the token
+func getSidecarTokenVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: SIDECAR_TOKEN_VOLUME,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"},
+					},
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"},
+							Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getHeartbeatProbe is the liveness probe of the game containers, which asks the sidecar if the game missed its heartbeat deadline
+// The probe starts after the deadline, so a restarted game has as long to send its first heartbeat as it has between heartbeats
+func getHeartbeatProbe(port int, deadline time.Duration) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/heartbeat",
+				Port: intstr.FromInt32(int32(port)),
+			},
+		},
+		InitialDelaySeconds: int32(math.Ceil(deadline.Seconds())),
+	}
+}
+
 func GetNewPod(server *v1alpha1.Server, namespace string) *corev1.Pod {
 	labels := server.GetLabels()
 	if labels == nil {
@@ -117,3 +275,39 @@ func GetNewPod(server *v1alpha1.Server, namespace string) *corev1.Pod {
 	}
 	return pod
 }
+
+// IsPodReady checks if the pod has the Ready condition set to true
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// GetPodCrash checks if a container of the pod stopped, or the pod itself ended, and describes what happened.
+// It is meant for pods that do not restart their containers, as restarted containers do not stay terminated.
+func GetPodCrash(pod *corev1.Pod) (string, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil {
+			return fmt.Sprintf("container %s exited with code %d", status.Name, terminated.ExitCode), true
+		}
+	}
+	if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+		if pod.Status.Reason != "" {
+			return fmt.Sprintf("pod %s with reason %s", pod.Status.Phase, pod.Status.Reason), true
+		}
+		return fmt.Sprintf("pod %s", pod.Status.Phase), true
+	}
+	return "", false
+}
+
+// GetRestartCount sums how often the kubelet restarted the containers of the pod
+func GetRestartCount(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Pod Utility Testing", func() {
+	Context("When creating the pod of a server", func() {
+		newServer := func(policy v1alpha1.ServerRestartPolicy) *v1alpha1.Server {
+			port := 8080
+			image := "sidecar"
+			return &v1alpha1.Server{
+				ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: "default"},
+				Spec: v1alpha1.ServerSpec{
+					Pod:             corev1.PodSpec{Containers: []corev1.Container{{Name: "game", Image: "game"}}},
+					SidecarSettings: &v1alpha1.SidecarSettings{Port: &port, SidecarImage: &image},
+					RestartPolicy:   policy,
+				},
+			}
+		}
+
+		It("Should keep the restart policy of the pod when restarting in place", func() {
+			Expect(GetNewPod(newServer(v1alpha1.ServerRestartInPlace), "default").Spec.RestartPolicy).To(BeEmpty())
+		})
+
+		It("Should tell every container where the sidecar listens", func() {
+			pod := GetNewPod(newServer(v1alpha1.ServerRestartInPlace), "default")
+			for _, container := range pod.Spec.Containers {
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "SIDECAR_PORT", Value: "8080"}))
+			}
+		})
+
+		It("Should use the default sidecar settings when the server has none", func() {
+			server := newServer(v1alpha1.ServerRestartInPlace)
+			server.Spec.SidecarSettings = nil
+			pod := GetNewPod(server, "default")
+			Expect(pod.Spec.Containers[1].Image).To(Equal(DEFAULT_SIDECAR_IMAGE))
+			Expect(pod.Spec.Containers[1].Env).To(ContainElement(corev1.EnvVar{Name: "PORT", Value: "8080"}))
+			Expect(server.Spec.SidecarSettings).To(BeNil())
+		})
+
+		It("Should give the sidecar a volume for its state", func() {
+			pod := GetNewPod(newServer(v1alpha1.ServerRestartInPlace), "default")
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Name", SIDECAR_STATE_VOLUME)))
+			sidecar := pod.Spec.Containers[1]
+			Expect(sidecar.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: SIDECAR_STATE_VOLUME, MountPath: SIDECAR_STATE_PATH}))
+			Expect(sidecar.Env).To(ContainElement(corev1.EnvVar{Name: "STATE_FILE", Value: SIDECAR_STATE_PATH + "/state.json"}))
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(BeEmpty())
+		})
+
+		It("Should let the sidecar push its state in push mode", func() {
+			server := newServer(v1alpha1.ServerRestartInPlace)
+			pod := GetNewPod(server, "default")
+			Expect(pod.Spec.ServiceAccountName).To(BeEmpty())
+			Expect(pod.Spec.Containers[1].Env).ToNot(ContainElement(HaveField("Name", "SIDECAR_MODE")))
+
+			server = newServer(v1alpha1.ServerRestartInPlace)
+			server.Spec.SidecarSettings.Mode = v1alpha1.SidecarModePush
+			pod = GetNewPod(server, "default")
+			Expect(pod.Spec.ServiceAccountName).To(Equal("server-sidecar"))
+			Expect(pod.Spec.Containers[1].Env).To(ContainElement(corev1.EnvVar{Name: "SIDECAR_MODE", Value: "Push"}))
+			Expect(pod.Spec.Containers[1].Env).To(ContainElement(HaveField("Name", "POD_NAME")))
+			Expect(pod.Spec.Containers[1].Env).To(ContainElement(HaveField("Name", "POD_NAMESPACE")))
+
+			By("Only giving the token to the sidecar")
+			Expect(pod.Spec.AutomountServiceAccountToken).ToNot(BeNil())
+			Expect(*pod.Spec.AutomountServiceAccountToken).To(BeFalse())
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Name", SIDECAR_TOKEN_VOLUME)))
+			Expect(pod.Spec.Containers[1].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: SIDECAR_TOKEN_VOLUME, MountPath: SIDECAR_TOKEN_PATH, ReadOnly: true}))
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(BeEmpty())
+
+			By("Keeping the service account of the user")
+			server = newServer(v1alpha1.ServerRestartInPlace)
+			server.Spec.SidecarSettings.Mode = v1alpha1.SidecarModePush
+			server.Spec.Pod.ServiceAccountName = "game"
+			pod = GetNewPod(server, "default")
+			Expect(pod.Spec.ServiceAccountName).To(Equal("game"))
+			Expect(pod.Spec.AutomountServiceAccountToken).To(BeNil())
+			Expect(pod.Spec.Volumes).ToNot(ContainElement(HaveField("Name", SIDECAR_TOKEN_VOLUME)))
+		})
+
+		It("Should pass the heartbeat deadline to the sidecar", func() {
+			server := newServer(v1alpha1.ServerRestartInPlace)
+			Expect(GetNewPod(server, "default").Spec.Containers[1].Env).ToNot(ContainElement(HaveField("Name", "HEARTBEAT_DEADLINE")))
+			server = newServer(v1alpha1.ServerRestartInPlace)
+			server.Spec.SidecarSettings.HeartbeatDeadline = &metav1.Duration{Duration: time.Minute}
+			Expect(GetNewPod(server, "default").Spec.Containers[1].Env).To(ContainElement(corev1.EnvVar{Name: "HEARTBEAT_DEADLINE", Value: "1m0s"}))
+		})
+
+		It("Should restart a hung game in place with a liveness probe on the sidecar", func() {
+			server := newServer(v1alpha1.ServerRestartInPlace)
+			Expect(GetNewPod(server, "default").Spec.Containers[0].LivenessProbe).To(BeNil())
+
+			server = newServer(v1alpha1.ServerRestartInPlace)
+			server.Spec.SidecarSettings.HeartbeatDeadline = &metav1.Duration{Duration: 30 * time.Second}
+			pod := GetNewPod(server, "default")
+			probe := pod.Spec.Containers[0].LivenessProbe
+			Expect(probe).ToNot(BeNil())
+			Expect(probe.HTTPGet.Path).To(Equal("/heartbeat"))
+			Expect(probe.HTTPGet.Port.IntValue()).To(Equal(8080))
+			Expect(probe.InitialDelaySeconds).To(BeEquivalentTo(30))
+			Expect(pod.Spec.Containers[1].LivenessProbe).To(BeNil())
+
+			By("Keeping the liveness probe of the user")
+			server = newServer(v1alpha1.ServerRestartInPlace)
+			server.Spec.SidecarSettings.HeartbeatDeadline = &metav1.Duration{Duration: 30 * time.Second}
+			server.Spec.Pod.Containers[0].LivenessProbe = &corev1.Probe{InitialDelaySeconds: 5}
+			Expect(GetNewPod(server, "default").Spec.Containers[0].LivenessProbe.InitialDelaySeconds).To(BeEquivalentTo(5))
+
+			By("Leaving a hung game to the operator with the Replace policy")
+			server = newServer(v1alpha1.ServerRestartReplace)
+			server.Spec.SidecarSettings.HeartbeatDeadline = &metav1.Duration{Duration: 30 * time.Second}
+			Expect(GetNewPod(server, "default").Spec.Containers[0].LivenessProbe).To(BeNil())
+		})
+
+		It("Should never restart the containers when the operator handles crashes", func() {
+			Expect(GetNewPod(newServer(v1alpha1.ServerRestartReplace), "default").Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(GetNewPod(newServer(v1alpha1.ServerRestartFail), "default").Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		})
+	})
+
+	Context("When checking the pod for crashes", func() {
+		It("Should not report running containers", func() {
+			pod := &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "game", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, RestartCount: 2},
+					{Name: "fallernetes-sidecar", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, RestartCount: 1},
+				},
+			}}
+			_, crashed := GetPodCrash(pod)
+			Expect(crashed).To(BeFalse())
+			Expect(GetRestartCount(pod)).To(BeEquivalentTo(3))
+		})
+
+		It("Should report a terminated container while the pod is running", func() {
+			pod := &corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "game", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137}}},
+				},
+			}}
+			crash, crashed := GetPodCrash(pod)
+			Expect(crashed).To(BeTrue())
+			Expect(crash).To(Equal("container game exited with code 137"))
+		})
+
+		It("Should report a failed pod", func() {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}}
+			crash, crashed := GetPodCrash(pod)
+			Expect(crashed).To(BeTrue())
+			Expect(crash).To(Equal("pod Failed with reason Evicted"))
+		})
+	})
+})
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var defaultRollingUpdateValue = intstr.FromString("25%")
+var defaultCanaryReplicas = intstr.FromString("10%")
+
+// GetCanaryReplicas calculates how many replicas the canary fleet runs, it is always between 1 and the desired replicas
+func GetCanaryReplicas(desired int32, canary *v1alpha1.CanaryUpdate) (int32, error) {
+	value := &defaultCanaryReplicas
+	if canary != nil && canary.Replicas != nil {
+		value = canary.Replicas
+	}
+	replicas, err := intstr.GetScaledValueFromIntOrPercent(value, int(desired), true)
+	if err != nil {
+		return 0, fmt.Errorf("invalid canary replicas: %w", err)
+	}
+	return max(1, min(int32(replicas), desired)), nil
+}
+
+// GetRollingUpdateReplicas calculates the replicas of the new fleet and the total replicas of the old fleets for the next step of a rolling update.
+// The new fleet grows as far as maxSurge allows, while the old fleets only shrink as long as maxUnavailable is respected.
+// Old servers that are not ready do not count as available, so they can always be removed.
+func GetRollingUpdateReplicas(desired int32, rolling *v1alpha1.RollingUpdate, newFleet *v1alpha1.Fleet, oldFleets []v1alpha1.Fleet) (int32, int32, error) {
+	maxSurge, maxUnavailable, err := resolveRollingUpdate(desired, rolling)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var oldReplicas, oldReady int32
+	for _, fleet := range oldFleets {
+		oldReplicas += fleet.Spec.Scaling.Replicas
+		oldReady += min(fleet.Status.ReadyReplicas, fleet.Spec.Scaling.Replicas)
+	}
+
+	newReplicas := min(newFleet.Spec.Scaling.Replicas, desired)
+	if allowed := desired + maxSurge - newReplicas - oldReplicas; allowed > 0 {
+		newReplicas = min(desired, newReplicas+allowed)
+	}
+
+	minAvailable := desired - maxUnavailable
+	available := min(newFleet.Status.ReadyReplicas, newFleet.Spec.Scaling.Replicas) + oldReady
+	scaleDown := (oldReplicas - oldReady) + max(0, available-minAvailable)
+	oldReplicas = max(0, oldReplicas-scaleDown)
+
+	return newReplicas, oldReplicas, nil
+}
+
+// resolveRollingUpdate turns maxSurge and maxUnavailable into absolute numbers.
+// Like deployments, surge is rounded up, unavailable is rounded down, and if both are 0 one server may be unavailable.
+func resolveRollingUpdate(desired int32, rolling *v1alpha1.RollingUpdate) (int32, int32, error) {
+	surgeValue, unavailableValue := &defaultRollingUpdateValue, &defaultRollingUpdateValue
+	if rolling != nil && rolling.MaxSurge != nil {
+		surgeValue = rolling.MaxSurge
+	}
+	if rolling != nil && rolling.MaxUnavailable != nil {
+		unavailableValue = rolling.MaxUnavailable
+	}
+
+	surge, err := intstr.GetScaledValueFromIntOrPercent(surgeValue, int(desired), true)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid maxSurge: %w", err)
+	}
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(unavailableValue, int(desired), false)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid maxUnavailable: %w", err)
+	}
+	if surge < 0 || unavailable < 0 {
+		return 0, 0, fmt.Errorf("maxSurge and maxUnavailable can not be negative")
+	}
+	if surge == 0 && unavailable == 0 {
+		unavailable = 1
+	}
+	return int32(surge), int32(unavailable), nil
+}
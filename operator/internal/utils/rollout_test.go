@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func fleetWithReplicas(replicas int32, ready int32) v1alpha1.Fleet {
+	return v1alpha1.Fleet{
+		Spec:   v1alpha1.FleetSpec{Scaling: v1alpha1.FleetScaling{Replicas: replicas}},
+		Status: v1alpha1.FleetStatus{ReadyReplicas: ready},
+	}
+}
+
+var _ = Describe("Rollout Utility Testing", func() {
+	Context("When calculating a rolling update step", func() {
+		It("Should move replicas step by step with the default strategy", func() {
+			newFleet := fleetWithReplicas(0, 0)
+			oldFleets := []v1alpha1.Fleet{fleetWithReplicas(4, 4)}
+
+			By("Starting the update")
+			newReplicas, oldReplicas, err := GetRollingUpdateReplicas(4, nil, &newFleet, oldFleets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newReplicas).To(Equal(int32(1)))
+			Expect(oldReplicas).To(Equal(int32(3)))
+
+			By("Surging while the new servers are not ready")
+			newFleet = fleetWithReplicas(1, 0)
+			oldFleets = []v1alpha1.Fleet{fleetWithReplicas(3, 3)}
+			newReplicas, oldReplicas, err = GetRollingUpdateReplicas(4, nil, &newFleet, oldFleets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newReplicas).To(Equal(int32(2)))
+			Expect(oldReplicas).To(Equal(int32(3)))
+
+			By("Scaling the old fleet down once new servers are ready")
+			newFleet = fleetWithReplicas(2, 2)
+			newReplicas, oldReplicas, err = GetRollingUpdateReplicas(4, nil, &newFleet, oldFleets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newReplicas).To(Equal(int32(2)))
+			Expect(oldReplicas).To(Equal(int32(1)))
+
+			By("Finishing the update")
+			newFleet = fleetWithReplicas(4, 4)
+			oldFleets = []v1alpha1.Fleet{fleetWithReplicas(1, 1)}
+			newReplicas, oldReplicas, err = GetRollingUpdateReplicas(4, nil, &newFleet, oldFleets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newReplicas).To(Equal(int32(4)))
+			Expect(oldReplicas).To(BeZero())
+		})
+
+		It("Should always remove old servers that are not ready", func() {
+			surge := intstr.FromInt32(1)
+			unavailable := intstr.FromInt32(0)
+			rolling := &v1alpha1.RollingUpdate{MaxSurge: &surge, MaxUnavailable: &unavailable}
+			newFleet := fleetWithReplicas(1, 1)
+			oldFleets := []v1alpha1.Fleet{fleetWithReplicas(4, 0)}
+
+			newReplicas, oldReplicas, err := GetRollingUpdateReplicas(4, rolling, &newFleet, oldFleets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newReplicas).To(Equal(int32(1)))
+			Expect(oldReplicas).To(BeZero())
+		})
+
+		It("Should allow one unavailable server if both values are 0", func() {
+			zero := intstr.FromInt32(0)
+			rolling := &v1alpha1.RollingUpdate{MaxSurge: &zero, MaxUnavailable: &zero}
+			newFleet := fleetWithReplicas(0, 0)
+			oldFleets := []v1alpha1.Fleet{fleetWithReplicas(2, 2)}
+
+			newReplicas, oldReplicas, err := GetRollingUpdateReplicas(2, rolling, &newFleet, oldFleets)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newReplicas).To(BeZero())
+			Expect(oldReplicas).To(Equal(int32(1)))
+		})
+
+		It("Should fail on invalid values", func() {
+			invalid := intstr.FromString("many")
+			rolling := &v1alpha1.RollingUpdate{MaxSurge: &invalid}
+			newFleet := fleetWithReplicas(0, 0)
+			_, _, err := GetRollingUpdateReplicas(2, rolling, &newFleet, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When calculating the canary replicas", func() {
+		It("Should use a percentage of the replicas rounded up", func() {
+			replicas, err := GetCanaryReplicas(20, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(2)))
+
+			replicas, err = GetCanaryReplicas(3, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(1)))
+		})
+
+		It("Should stay between one and the desired replicas", func() {
+			zero := intstr.FromInt32(0)
+			replicas, err := GetCanaryReplicas(4, &v1alpha1.CanaryUpdate{Replicas: &zero})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(1)))
+
+			many := intstr.FromInt32(10)
+			replicas, err = GetCanaryReplicas(4, &v1alpha1.CanaryUpdate{Replicas: &many})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas).To(Equal(int32(4)))
+		})
+
+		It("Should fail on invalid values", func() {
+			invalid := intstr.FromString("some")
+			_, err := GetCanaryReplicas(4, &v1alpha1.CanaryUpdate{Replicas: &invalid})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When splitting the fleets of a gametype", func() {
+		It("Should pick the newest fleet matching the spec", func() {
+			baseTime := time.Now()
+			deletionTime := metav1.Now()
+			spec := v1alpha1.FleetSpec{ServerSpec: v1alpha1.ServerSpec{Pod: v1.PodSpec{Containers: []v1.Container{{Name: "game", Image: "new"}}}}}
+			oldSpec := v1alpha1.FleetSpec{ServerSpec: v1alpha1.ServerSpec{Pod: v1.PodSpec{Containers: []v1.Container{{Name: "game", Image: "old"}}}}}
+			gametype := &v1alpha1.GameType{Spec: v1alpha1.GameTypeSpec{FleetSpec: spec}}
+			fleets := &v1alpha1.FleetList{Items: []v1alpha1.Fleet{
+				{ObjectMeta: metav1.ObjectMeta{Name: "current", CreationTimestamp: metav1.Time{Time: baseTime.Add(time.Hour)}}, Spec: spec},
+				{ObjectMeta: metav1.ObjectMeta{Name: "old-matching", CreationTimestamp: metav1.Time{Time: baseTime.Add(time.Minute)}}, Spec: spec},
+				{ObjectMeta: metav1.ObjectMeta{Name: "oldest", CreationTimestamp: metav1.Time{Time: baseTime}}, Spec: oldSpec},
+				{ObjectMeta: metav1.ObjectMeta{Name: "deleting", CreationTimestamp: metav1.Time{Time: baseTime}, DeletionTimestamp: &deletionTime}, Spec: oldSpec},
+			}}
+
+			current, old := GetFleetsForUpdate(gametype, fleets)
+			Expect(current).ToNot(BeNil())
+			Expect(current.Name).To(Equal("current"))
+			Expect(old).To(HaveLen(2))
+			Expect(old[0].Name).To(Equal("oldest"))
+			Expect(old[1].Name).To(Equal("old-matching"))
+
+			By("Returning no current fleet when nothing matches")
+			gametype.Spec.FleetSpec = v1alpha1.FleetSpec{ServerSpec: v1alpha1.ServerSpec{Pod: v1.PodSpec{Containers: []v1.Container{{Name: "game", Image: "newer"}}}}}
+			current, old = GetFleetsForUpdate(gametype, fleets)
+			Expect(current).To(BeNil())
+			Expect(old).To(HaveLen(3))
+		})
+	})
+	Context("When recording revisions of a gametype", func() {
+		fleetWithImage := func(name, image string) *v1alpha1.Fleet {
+			return &v1alpha1.Fleet{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       v1alpha1.FleetSpec{ServerSpec: v1alpha1.ServerSpec{Pod: v1.PodSpec{Containers: []v1.Container{{Name: "game", Image: image}}}}},
+			}
+		}
+
+		It("Should number revisions and drop the oldest above the limit", func() {
+			limit := int32(2)
+			gametype := &v1alpha1.GameType{Spec: v1alpha1.GameTypeSpec{RevisionHistoryLimit: &limit}}
+			Expect(AddRevision(gametype, fleetWithImage("first", "v1"), "v1", metav1.Now())).To(BeEmpty())
+			Expect(AddRevision(gametype, fleetWithImage("second", "v2"), "v2", metav1.Now())).To(BeEmpty())
+			dropped := AddRevision(gametype, fleetWithImage("third", "v3"), "v3", metav1.Now())
+
+			Expect(dropped).To(HaveLen(1))
+			Expect(dropped[0].Hash).To(Equal("v1"))
+			Expect(gametype.Status.CurrentRevision).To(Equal(int64(3)))
+			Expect(gametype.Status.Revisions).To(HaveLen(2))
+			Expect(gametype.Status.Revisions[0].FleetName).To(Equal("second"))
+			Expect(FindRevision(gametype, 1)).To(BeNil())
+			Expect(FindRevision(gametype, 3).FleetName).To(Equal("third"))
+		})
+
+		It("Should cap the revision history limit", func() {
+			limit := int32(1000)
+			gametype := &v1alpha1.GameType{Spec: v1alpha1.GameTypeSpec{RevisionHistoryLimit: &limit}}
+			for i := range maxRevisionHistoryLimit + 1 {
+				AddRevision(gametype, fleetWithImage("fleet", "image"), strconv.Itoa(int(i)), metav1.Now())
+			}
+			Expect(gametype.Status.Revisions).To(HaveLen(int(maxRevisionHistoryLimit)))
+		})
+
+		It("Should replace a revision with the same server spec", func() {
+			gametype := &v1alpha1.GameType{}
+			AddRevision(gametype, fleetWithImage("first", "v1"), "v1", metav1.Now())
+			AddRevision(gametype, fleetWithImage("second", "v2"), "v2", metav1.Now())
+			Expect(AddRevision(gametype, fleetWithImage("rollback", "v1"), "v1", metav1.Now())).To(BeEmpty())
+
+			Expect(gametype.Status.CurrentRevision).To(Equal(int64(3)))
+			Expect(gametype.Status.Revisions).To(HaveLen(2))
+			Expect(gametype.Status.Revisions[0].Revision).To(Equal(int64(2)))
+			Expect(gametype.Status.Revisions[1].FleetName).To(Equal("rollback"))
+		})
+
+		It("Should hash server specs by their content", func() {
+			first, err := GetRevisionHash(&fleetWithImage("first", "v1").Spec.ServerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			same, err := GetRevisionHash(&fleetWithImage("second", "v1").Spec.ServerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			other, err := GetRevisionHash(&fleetWithImage("third", "v2").Spec.ServerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first).To(Equal(same))
+			Expect(first).ToNot(Equal(other))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,3 +26,40 @@ func CreateServerForFleet(fleet v1alpha1.Fleet, namespace string) *v1alpha1.Serv
 
 	return &server
 }
+
+// GetServerStateForPod maps the phase and readiness of the pod of a server to the lifecycle state of the server.
+// It is only meant for servers which are not being deleted, as those states are driven by the deletion process.
+func GetServerStateForPod(pod *corev1.Pod) v1alpha1.ServerState {
+	switch pod.Status.Phase {
+	case corev1.PodFailed, corev1.PodSucceeded:
+		return v1alpha1.ServerStateFailed
+	case corev1.PodRunning:
+		if IsPodReady(pod) {
+			return v1alpha1.ServerStateReady
+		}
+		return v1alpha1.ServerStateStarting
+	}
+	return v1alpha1.ServerStateStarting
+}
+
+// GetPlayerTotals sums the reported players and the configured capacity of the servers
+func GetPlayerTotals(servers *v1alpha1.ServerList) (players int32, capacity int32) {
+	for _, server := range servers.Items {
+		players += server.Status.Players
+		if server.Spec.GameInfo != nil && server.Spec.GameInfo.Capacity != nil {
+			capacity += int32(*server.Spec.GameInfo.Capacity)
+		}
+	}
+	return players, capacity
+}
+
+// GetReadyServerCount counts the servers that are ready or allocated
+func GetReadyServerCount(servers *v1alpha1.ServerList) int32 {
+	var ready int32
+	for _, server := range servers.Items {
+		if server.Status.State == v1alpha1.ServerStateReady || server.Status.State == v1alpha1.ServerStateAllocated {
+			ready++
+		}
+	}
+	return ready
+}
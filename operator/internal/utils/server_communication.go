@@ -3,35 +3,98 @@ package utils
 import (
 	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
-	"strconv"
 	"time"
 )
 
 type Deletion interface {
 	IsDeletionAllowed(*v1alpha1.Server, *corev1.Pod) (bool, error)
+	IsGameDeleteAllowed(*v1alpha1.Server, *corev1.Pod) (bool, error)
 }
 
 type ProdDeletionChecker struct{}
 
+type PlayerCounter interface {
+	GetPlayerCount(*v1alpha1.Server, *corev1.Pod) (int, error)
+}
+
+type ProdPlayerCounter struct{}
+
+type HeartbeatChecker interface {
+	IsHeartbeatHealthy(*v1alpha1.Server, *corev1.Pod) (bool, error)
+}
+
+type ProdHeartbeatChecker struct{}
+
+// IsHeartbeatHealthy asks the sidecar of the server if the game sent a heartbeat within its deadline, or reads what it pushed in push mode
+func (h ProdHeartbeatChecker) IsHeartbeatHealthy(server *v1alpha1.Server, pod *corev1.Pod) (bool, error) {
+	if IsPushMode(server) {
+		return IsPushedHeartbeatHealthy(pod), nil
+	}
+	return IsHeartbeatHealthy(pod, getSidecarPort(server))
+}
+
+// GetPlayerCount asks the sidecar of the server how many players the game reported, or reads what it pushed in push mode
+func (p ProdPlayerCounter) GetPlayerCount(server *v1alpha1.Server, pod *corev1.Pod) (int, error) {
+	if IsPushMode(server) {
+		return GetPushedPlayerCount(pod)
+	}
+	return GetPlayerCount(pod, getSidecarPort(server))
+}
+
+// IsGameDeleteAllowed asks the sidecar if the game allowed the deletion of the server, or reads what it pushed in push mode.
+// Unlike IsDeletionAllowed, it does not request a shutdown.
+func (p ProdDeletionChecker) IsGameDeleteAllowed(server *v1alpha1.Server, pod *corev1.Pod) (bool, error) {
+	if IsPushMode(server) {
+		return IsPushedDeleteAllowed(pod), nil
+	}
+	return IsDeleteAllowed(pod, getSidecarPort(server))
+}
+
+// IsDeletionAllowed checks if the server can be deleted, asking the sidecar if needed.
+// It also moves the state of the server forward, so the caller only has to persist it.
 func (p ProdDeletionChecker) IsDeletionAllowed(server *v1alpha1.Server, pod *corev1.Pod) (bool, error) {
 	if pod.Status.Phase != corev1.PodRunning {
+		server.Status.State = v1alpha1.ServerStateDeleteAllowed
 		return true, nil
 	}
 	if server.Spec.AllowForceDelete {
+		server.Status.State = v1alpha1.ServerStateDeleteAllowed
 		return true, nil
 	}
 
 	if server.Spec.TimeOut != nil {
 		timeWhenAllowDelete := server.GetDeletionTimestamp().Time.Add(server.Spec.TimeOut.Duration)
 		if timeWhenAllowDelete.Before(time.Now()) {
+			server.Status.State = v1alpha1.ServerStateDeleteAllowed
 			return true, nil
 		}
 	}
-	port := strconv.Itoa(*server.Spec.SidecarSettings.Port)
+	port := getSidecarPort(server)
+	if IsPushMode(server) {
+		// The shutdown request is only sent until the sidecar acknowledged it, the rest is read from the pod
+		if !IsPushedShutdownAcknowledged(pod) {
+			if err := RequestShutdown(pod, port); err != nil {
+				return false, err
+			}
+		}
+		server.Status.State = v1alpha1.ServerStateDraining
+		allowed := IsPushedDeleteAllowed(pod)
+		if allowed {
+			server.Status.State = v1alpha1.ServerStateDeleteAllowed
+		}
+		return allowed, nil
+	}
 	err := RequestShutdown(pod, port)
 	if err != nil {
 		return false, err
 	}
+	server.Status.State = v1alpha1.ServerStateDraining
 	allowed, err := IsDeleteAllowed(pod, port)
-	return allowed, err
+	if err != nil {
+		return false, err
+	}
+	if allowed {
+		server.Status.State = v1alpha1.ServerStateDeleteAllowed
+	}
+	return allowed, nil
 }
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Server Utility Testing", func() {
+	Context("When getting the state of a server from its pod", func() {
+		It("Should be starting while the pod is pending", func() {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}}
+			Expect(GetServerStateForPod(pod)).To(Equal(v1alpha1.ServerStateStarting))
+		})
+
+		It("Should be starting while the pod is running but not ready", func() {
+			pod := &corev1.Pod{Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			}}
+			Expect(GetServerStateForPod(pod)).To(Equal(v1alpha1.ServerStateStarting))
+		})
+
+		It("Should be ready when the pod is running and ready", func() {
+			pod := &corev1.Pod{Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			}}
+			Expect(GetServerStateForPod(pod)).To(Equal(v1alpha1.ServerStateReady))
+		})
+
+		It("Should be failed when the pod has exited", func() {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed}}
+			Expect(GetServerStateForPod(pod)).To(Equal(v1alpha1.ServerStateFailed))
+			pod.Status.Phase = corev1.PodSucceeded
+			Expect(GetServerStateForPod(pod)).To(Equal(v1alpha1.ServerStateFailed))
+		})
+	})
+
+	Context("When summing the players of servers", func() {
+		It("Should sum players and the capacity of servers that have one", func() {
+			capacity := 10
+			servers := &v1alpha1.ServerList{Items: []v1alpha1.Server{
+				{
+					Spec:   v1alpha1.ServerSpec{GameInfo: &v1alpha1.GameInfo{Capacity: &capacity}},
+					Status: v1alpha1.ServerStatus{Players: 4},
+				},
+				{
+					Spec:   v1alpha1.ServerSpec{GameInfo: &v1alpha1.GameInfo{Capacity: &capacity}},
+					Status: v1alpha1.ServerStatus{Players: 7},
+				},
+				{
+					Status: v1alpha1.ServerStatus{Players: 1},
+				},
+			}}
+			players, total := GetPlayerTotals(servers)
+			Expect(players).To(Equal(int32(12)))
+			Expect(total).To(Equal(int32(20)))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+)
+
+// IsPushMode returns true if the sidecar of the server pushes its state to the annotations of its pod
+func IsPushMode(server *v1alpha1.Server) bool {
+	return server.Spec.SidecarSettings != nil && server.Spec.SidecarSettings.Mode == v1alpha1.SidecarModePush
+}
+
+// IsPushedDeleteAllowed reads if the game allowed the deletion, from the annotations the sidecar pushed
+func IsPushedDeleteAllowed(pod *corev1.Pod) bool {
+	return pod.Annotations[v1alpha1.DeleteAllowedAnnotation] == "true"
+}
+
+// IsPushedShutdownAcknowledged reads if the sidecar received the shutdown request, from the annotations the sidecar pushed
+func IsPushedShutdownAcknowledged(pod *corev1.Pod) bool {
+	return pod.Annotations[v1alpha1.ShutdownAcknowledgedAnnotation] == "true"
+}
+
+// IsPushedHeartbeatHealthy reads if the game sent a heartbeat within its deadline, from the annotations the sidecar pushed.
+// The game is healthy before the first push, like it is when the sidecar starts.
+func IsPushedHeartbeatHealthy(pod *corev1.Pod) bool {
+	return pod.Annotations[v1alpha1.HeartbeatHealthyAnnotation] != "false"
+}
+
+// GetPushedPlayerCount reads the player count from the annotations the sidecar pushed, which is zero before the first push
+func GetPushedPlayerCount(pod *corev1.Pod) (int, error) {
+	players, ok := pod.Annotations[v1alpha1.PlayersAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(players)
+	if err != nil {
+		return 0, fmt.Errorf("invalid player count annotation %q: %w", players, err)
+	}
+	return count, nil
+}
+
+// GetSidecarServiceAccountName returns the name of the service account the sidecar of the server pushes its state with
+func GetSidecarServiceAccountName(server *v1alpha1.Server) string {
+	return server.Name + "-sidecar"
+}
+
+// GetSidecarAccess returns the service account, role and role binding that let the sidecar of the server push its state.
+// Every server has its own service account, and the role only allows patching the pod of the server,
+// so a sidecar can not touch the pods of other servers.
+// The caller has to make the server the owner of all three, so they are removed together with the server.
+func GetSidecarAccess(server *v1alpha1.Server) (*corev1.ServiceAccount, *rbacv1.Role, *rbacv1.RoleBinding) {
+	meta := metav1.ObjectMeta{Name: GetSidecarServiceAccountName(server), Namespace: server.Namespace}
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: meta}
+	role := &rbacv1.Role{
+		ObjectMeta: meta,
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"pods"},
+				ResourceNames: []string{server.Name + "-pod"},
+				Verbs:         []string{"patch"},
+			},
+		},
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: meta,
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccount.Name,
+				Namespace: server.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+	}
+	return serviceAccount, role, roleBinding
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/MirrorStudios/fallernetes/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Sidecar Push Testing", func() {
+	port := 8080
+	newServer := func() *v1alpha1.Server {
+		return &v1alpha1.Server{
+			ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: "default"},
+			Spec: v1alpha1.ServerSpec{
+				SidecarSettings: &v1alpha1.SidecarSettings{Port: &port, Mode: v1alpha1.SidecarModePush},
+			},
+		}
+	}
+	newPod := func(annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "server-pod", Namespace: "default", Annotations: annotations},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	Context("When reading the pushed state", func() {
+		It("Should only use the annotations in push mode", func() {
+			server := newServer()
+			Expect(IsPushMode(server)).To(BeTrue())
+			server.Spec.SidecarSettings.Mode = v1alpha1.SidecarModePoll
+			Expect(IsPushMode(server)).To(BeFalse())
+			server.Spec.SidecarSettings = nil
+			Expect(IsPushMode(server)).To(BeFalse())
+		})
+
+		It("Should read the annotations of the pod", func() {
+			pod := newPod(nil)
+			Expect(IsPushedDeleteAllowed(pod)).To(BeFalse())
+			Expect(IsPushedShutdownAcknowledged(pod)).To(BeFalse())
+			Expect(IsPushedHeartbeatHealthy(pod)).To(BeTrue())
+			players, err := GetPushedPlayerCount(pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(players).To(BeZero())
+
+			pod = newPod(map[string]string{
+				v1alpha1.DeleteAllowedAnnotation:        "true",
+				v1alpha1.ShutdownAcknowledgedAnnotation: "true",
+				v1alpha1.PlayersAnnotation:              "7",
+			})
+			Expect(IsPushedDeleteAllowed(pod)).To(BeTrue())
+			Expect(IsPushedShutdownAcknowledged(pod)).To(BeTrue())
+			players, err = ProdPlayerCounter{}.GetPlayerCount(newServer(), pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(players).To(Equal(7))
+
+			_, err = GetPushedPlayerCount(newPod(map[string]string{v1alpha1.PlayersAnnotation: "many"}))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should read the heartbeat from the annotations without asking the sidecar", func() {
+			healthy, err := ProdHeartbeatChecker{}.IsHeartbeatHealthy(newServer(), newPod(map[string]string{v1alpha1.HeartbeatHealthyAnnotation: "true"}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(healthy).To(BeTrue())
+			healthy, err = ProdHeartbeatChecker{}.IsHeartbeatHealthy(newServer(), newPod(map[string]string{v1alpha1.HeartbeatHealthyAnnotation: "false"}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(healthy).To(BeFalse())
+		})
+
+		It("Should check the deletion without asking the sidecar once the shutdown was acknowledged", func() {
+			server := newServer()
+			pod := newPod(map[string]string{v1alpha1.ShutdownAcknowledgedAnnotation: "true"})
+			allowed, err := ProdDeletionChecker{}.IsDeletionAllowed(server, pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(server.Status.State).To(Equal(v1alpha1.ServerStateDraining))
+
+			pod.Annotations[v1alpha1.DeleteAllowedAnnotation] = "true"
+			allowed, err = ProdDeletionChecker{}.IsDeletionAllowed(server, pod)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			Expect(server.Status.State).To(Equal(v1alpha1.ServerStateDeleteAllowed))
+		})
+
+		It("Should read the pod from the client when the fleet checks the deletion", func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			var c client.Client = fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(newPod(map[string]string{v1alpha1.DeleteAllowedAnnotation: "true"})).Build()
+			allowed, err := ProdDeletionChecker{}.isDeleteAllowed(context.Background(), newServer(), &c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+	})
+
+	Context("When setting up the sidecar access", func() {
+		It("Should only allow the service account of the server to patch the pod of the server", func() {
+			server := newServer()
+			server.Namespace = "games"
+			serviceAccount, role, roleBinding := GetSidecarAccess(server)
+			Expect(serviceAccount.Name).To(Equal("server-sidecar"))
+			Expect(serviceAccount.Namespace).To(Equal("games"))
+			Expect(role.Name).To(Equal("server-sidecar"))
+			Expect(role.Namespace).To(Equal("games"))
+			Expect(role.Rules).To(HaveLen(1))
+			Expect(role.Rules[0].Resources).To(Equal([]string{"pods"}))
+			Expect(role.Rules[0].ResourceNames).To(Equal([]string{"server-pod"}))
+			Expect(role.Rules[0].Verbs).To(Equal([]string{"patch"}))
+			Expect(roleBinding.Name).To(Equal(role.Name))
+			Expect(roleBinding.RoleRef.Name).To(Equal(role.Name))
+			Expect(roleBinding.Subjects[0].Name).To(Equal(serviceAccount.Name))
+			Expect(roleBinding.Subjects[0].Namespace).To(Equal("games"))
+		})
+	})
+})
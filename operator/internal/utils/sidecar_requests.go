@@ -18,6 +18,15 @@ type shutdownRequest struct {
 	Shutdown bool `json:"shutdown"`
 }
 
+type playersRequest struct {
+	Players  int `json:"players"`
+	Capacity int `json:"capacity"`
+}
+
+type heartbeatRequest struct {
+	Healthy bool `json:"healthy"`
+}
+
 // IsDeleteAllowed sents a request to API/allow_delete to ask the server if it can be shutdown and deleted
 func IsDeleteAllowed(pod *v1.Pod, port string) (bool, error) {
 	client := &http.Client{
@@ -70,6 +79,55 @@ func RequestShutdown(pod *v1.Pod, port string) error {
 	return nil
 }
 
+// GetPlayerCount sends a request to API/players to get the amount of players the server reported
+func GetPlayerCount(pod *v1.Pod, port string) (int, error) {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Get(buildPodBaseAddress(pod, port) + "players")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, errors.New("GET request returned: " + resp.Status)
+	}
+
+	var request playersRequest
+	err = json.NewDecoder(resp.Body).Decode(&request)
+	if err != nil {
+		return 0, err
+	}
+	return request.Players, nil
+}
+
+// IsHeartbeatHealthy sends a request to API/heartbeat to check if the game sent a heartbeat within its deadline
+func IsHeartbeatHealthy(pod *v1.Pod, port string) (bool, error) {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Get(buildPodBaseAddress(pod, port) + "heartbeat")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// A missed deadline is answered with 503, so the same endpoint works as liveness probe
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return false, errors.New("GET request returned: " + resp.Status)
+	}
+
+	var request heartbeatRequest
+	err = json.NewDecoder(resp.Body).Decode(&request)
+	if err != nil {
+		return false, err
+	}
+	return request.Healthy, nil
+}
+
 func buildPodBaseAddress(pod *v1.Pod, port string) string {
 	return fmt.Sprintf("http://%s:%s/", pod.Status.PodIP, port)
 }
@@ -65,11 +65,21 @@ func (d *GameTypeAutoscalerCustomDefaulter) Default(ctx context.Context, obj run
 	}
 	gametypeautoscalerlog.Info("Defaulting for GameTypeAutoscaler", "name", gametypeautoscaler.GetName())
 
-	// TODO(user): fill in your defaulting logic.
+	defaultTargetRef(gametypeautoscaler)
 
 	return nil
 }
 
+// defaultTargetRef sets the targetRef of autoscalers that only have a gameTypeName
+func defaultTargetRef(autoscaler *gameserverv1alpha1.GameTypeAutoscaler) {
+	if autoscaler.Spec.TargetRef == nil && autoscaler.Spec.GameTypeName != "" {
+		autoscaler.Spec.TargetRef = &gameserverv1alpha1.TargetRef{
+			Kind: gameserverv1alpha1.GameTypeTarget,
+			Name: autoscaler.Spec.GameTypeName,
+		}
+	}
+}
+
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
 // Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
@@ -94,9 +104,7 @@ func (v *GameTypeAutoscalerCustomValidator) ValidateCreate(ctx context.Context,
 	}
 	gametypeautoscalerlog.Info("Validation for GameTypeAutoscaler upon creation", "name", gametypeautoscaler.GetName())
 
-	// TODO(user): fill in your validation logic upon object creation.
-
-	return nil, nil
+	return validateTarget(gametypeautoscaler)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type GameTypeAutoscaler.
@@ -107,9 +115,7 @@ func (v *GameTypeAutoscalerCustomValidator) ValidateUpdate(ctx context.Context,
 	}
 	gametypeautoscalerlog.Info("Validation for GameTypeAutoscaler upon update", "name", gametypeautoscaler.GetName())
 
-	// TODO(user): fill in your validation logic upon object update.
-
-	return nil, nil
+	return validateTarget(gametypeautoscaler)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type GameTypeAutoscaler.
@@ -124,3 +130,22 @@ func (v *GameTypeAutoscalerCustomValidator) ValidateDelete(ctx context.Context,
 
 	return nil, nil
 }
+
+// validateTarget checks that the autoscaler has a target, and that the kind of the target can be scaled
+func validateTarget(autoscaler *gameserverv1alpha1.GameTypeAutoscaler) (admission.Warnings, error) {
+	target := autoscaler.Spec.GetTarget()
+	if target.Name == "" {
+		return nil, fmt.Errorf("either a targetRef or a gameTypeName is required")
+	}
+	if !gameserverv1alpha1.IsValidTargetKind(target.Kind) {
+		return nil, fmt.Errorf("%s is not a supported target kind, it has to be %s or %s",
+			target.Kind, gameserverv1alpha1.GameTypeTarget, gameserverv1alpha1.FleetTarget)
+	}
+	spec := autoscaler.Spec
+	if spec.TargetRef != nil && spec.GameTypeName != "" &&
+		(spec.TargetRef.Kind != gameserverv1alpha1.GameTypeTarget || spec.TargetRef.Name != spec.GameTypeName) {
+		return admission.Warnings{fmt.Sprintf("gameTypeName %s is ignored, the autoscaler scales the %s %s of the targetRef",
+			spec.GameTypeName, target.Kind, target.Name)}, nil
+	}
+	return nil, nil
+}
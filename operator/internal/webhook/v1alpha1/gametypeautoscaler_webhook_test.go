@@ -49,39 +49,46 @@ var _ = Describe("GameTypeAutoscaler Webhook", func() {
 	})
 
 	Context("When creating GameTypeAutoscaler under Defaulting Webhook", func() {
-		// TODO (user): Add logic for defaulting webhooks
-		// Example:
-		// It("Should apply defaults when a required field is empty", func() {
-		//     By("simulating a scenario where defaults should be applied")
-		//     obj.SomeFieldWithDefault = ""
-		//     By("calling the Default method to apply defaults")
-		//     defaulter.Default(ctx, obj)
-		//     By("checking that the default values are set")
-		//     Expect(obj.SomeFieldWithDefault).To(Equal("default_value"))
-		// })
+		It("Should set the targetRef from the gameTypeName", func() {
+			obj.Spec.GameTypeName = "game"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.TargetRef).To(Equal(&gameserverv1alpha1.TargetRef{Kind: gameserverv1alpha1.GameTypeTarget, Name: "game"}))
+		})
+
+		It("Should keep an existing targetRef", func() {
+			obj.Spec.GameTypeName = "game"
+			obj.Spec.TargetRef = &gameserverv1alpha1.TargetRef{Kind: gameserverv1alpha1.FleetTarget, Name: "fleet"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.TargetRef.Kind).To(Equal(gameserverv1alpha1.FleetTarget))
+		})
 	})
 
 	Context("When creating or updating GameTypeAutoscaler under Validating Webhook", func() {
-		// TODO (user): Add logic for validating webhooks
-		// Example:
-		// It("Should deny creation if a required field is missing", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = ""
-		//     Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
-		// })
-		//
-		// It("Should admit creation if all required fields are present", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = "valid_value"
-		//     Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
-		// })
-		//
-		// It("Should validate updates correctly", func() {
-		//     By("simulating a valid update scenario")
-		//     oldObj.SomeRequiredField = "updated_value"
-		//     obj.SomeRequiredField = "updated_value"
-		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
-		// })
+		It("Should deny creation without a target", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny unsupported target kinds", func() {
+			obj.Spec.TargetRef = &gameserverv1alpha1.TargetRef{Kind: "Deployment", Name: "game"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should admit gametypes and fleets", func() {
+			obj.Spec.GameTypeName = "game"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+			obj.Spec.GameTypeName = ""
+			obj.Spec.TargetRef = &gameserverv1alpha1.TargetRef{Kind: gameserverv1alpha1.FleetTarget, Name: "fleet"}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+		})
+
+		It("Should warn when the gameTypeName is ignored", func() {
+			obj.Spec.GameTypeName = "game"
+			obj.Spec.TargetRef = &gameserverv1alpha1.TargetRef{Kind: gameserverv1alpha1.FleetTarget, Name: "fleet"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
 	})
 
 })
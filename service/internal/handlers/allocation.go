@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-service/internal/app"
+	"github.com/MirrorStudios/fallernetes-service/internal/kube"
+	"log"
+	"net/http"
+)
+
+type AllocateServerRequest struct {
+	Allocation *kube.ServerAllocation `json:"allocation"`
+}
+
+// AllocateServer is used to claim a ready server of a fleet or gametype, so it is not scaled down or handed out again.
+// It responds with the name, pod IP and node of the allocated server.
+func AllocateServer(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var request AllocateServerRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			log.Printf("Error decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if request.Allocation == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		status, err := kube.AllocateServer(context.WithValue(r.Context(), "kube", "allocate-server"), request.Allocation, a.DynamicClient)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Printf("Error allocating server: %v\n", err)
+			e := map[string]string{
+				"message": "Error allocating server",
+				"error":   err.Error(),
+			}
+			err := json.NewEncoder(w).Encode(e)
+			if err != nil {
+				log.Println("Error writing response:", err)
+				return
+			}
+			return
+		}
+
+		switch status.State {
+		case kube.AllocationStateUnAllocated:
+			w.WriteHeader(http.StatusNotFound)
+		case kube.AllocationStateInvalid:
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+		err = json.NewEncoder(w).Encode(status)
+		if err != nil {
+			log.Println("Error writing response:", err)
+			return
+		}
+	})
+}
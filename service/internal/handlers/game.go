@@ -13,6 +13,16 @@ type CreateGameRequest struct {
 	Game *kube.GameType `json:"game"`
 }
 
+type CanaryRequest struct {
+	Metadata *kube.Metadata `json:"metadata"`
+	Action   string         `json:"action"`
+}
+
+type RollbackRequest struct {
+	Metadata *kube.Metadata `json:"metadata"`
+	Revision int64          `json:"revision"`
+}
+
 // CreateGame is used to create a new kube.GameType in the cluster
 func CreateGame(a *app.App) func(http.ResponseWriter, *http.Request) {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -105,3 +115,85 @@ func DeleteGame(a *app.App) func(http.ResponseWriter, *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 }
+
+// CanaryGame is used to promote or abort the running canary of a game, using the namespace and name
+func CanaryGame(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var request CanaryRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			log.Printf("Error decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if request.Metadata == nil || (request.Action != kube.CanaryPromote && request.Action != kube.CanaryAbort) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		err = kube.SetCanaryAction(context.WithValue(context.Background(), "kube", "canary-game"), *request.Metadata, request.Action, a.DynamicClient)
+		if err != nil {
+			log.Printf("Error updating game canary: %v\n", err)
+			e := map[string]string{
+				"message": "Error updating game canary",
+				"error":   err.Error(),
+			}
+			jsonData, err := json.Marshal(e)
+			if err != nil {
+				log.Println("Error marshaling json:", err)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err = w.Write(jsonData)
+			if err != nil {
+				log.Println("Error writing response:", err)
+				return
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// RollbackGame is used to roll a game back to one of its revisions, using the namespace and name
+func RollbackGame(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var request RollbackRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			log.Printf("Error decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if request.Metadata == nil || request.Revision <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		err = kube.RollbackGame(context.WithValue(context.Background(), "kube", "rollback-game"), *request.Metadata, request.Revision, a.DynamicClient)
+		if err != nil {
+			log.Printf("Error rolling back game: %v\n", err)
+			e := map[string]string{
+				"message": "Error rolling back game",
+				"error":   err.Error(),
+			}
+			jsonData, err := json.Marshal(e)
+			if err != nil {
+				log.Println("Error marshaling json:", err)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err = w.Write(jsonData)
+			if err != nil {
+				log.Println("Error writing response:", err)
+				return
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,124 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+var AllocationGCR = schema.GroupVersionResource{
+	Group:    crdGroup,
+	Version:  crdVersion,
+	Resource: allocationResourceName,
+}
+
+// allocationTimeout is how long to wait for the operator to handle an allocation
+const allocationTimeout = 10 * time.Second
+
+type AllocationState string
+
+const (
+	AllocationStateAllocated   AllocationState = "Allocated"
+	AllocationStateUnAllocated AllocationState = "UnAllocated"
+	AllocationStateInvalid     AllocationState = "Invalid"
+)
+
+type ServerAllocationSpec struct {
+	FleetName    string                `json:"fleetName,omitempty"`
+	GameTypeName string                `json:"gameTypeName,omitempty"`
+	Selector     *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+type ServerAllocationStatus struct {
+	State      AllocationState `json:"state,omitempty"`
+	ServerName string          `json:"serverName,omitempty"`
+	PodIP      string          `json:"podIP,omitempty"`
+	NodeName   string          `json:"nodeName,omitempty"`
+	Message    string          `json:"message,omitempty"`
+}
+
+type ServerAllocation struct {
+	ApiVersion APIVersion           `json:"apiVersion"`
+	Kind       Kind                 `json:"kind"`
+	Metadata   Metadata             `json:"metadata"`
+	Spec       ServerAllocationSpec `json:"spec"`
+}
+
+// AllocateServer is used to create a ServerAllocation resource and wait for the operator to claim a server for it.
+// If no name is given, one is generated.
+// Allocations that did not claim a server are deleted again. The operator deletes an allocation that claimed a server together with the server,
+// deleting it earlier releases the server.
+func AllocateServer(ctx context.Context, allocation *ServerAllocation, client *dynamic.DynamicClient) (*ServerAllocationStatus, error) {
+	resource := client.Resource(AllocationGCR).Namespace(allocation.Metadata.Namespace)
+	allocationStruct, err := allocationToUnstructured(allocation)
+	if err != nil {
+		return nil, err
+	}
+	if allocationStruct.GetName() == "" {
+		allocationStruct.SetGenerateName("allocation-")
+	}
+	created, err := resource.Create(ctx, allocationStruct, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ServerAllocationStatus{}
+	err = wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, allocationTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := resource.Get(ctx, created.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		rawStatus, found, err := unstructured.NestedMap(current.Object, "status")
+		if err != nil || !found {
+			return false, err
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawStatus, status); err != nil {
+			return false, err
+		}
+		return status.State != "", nil
+	})
+	if err != nil {
+		deleteAllocation(resource, created.GetName())
+		return nil, fmt.Errorf("allocation %s was not handled: %w", created.GetName(), err)
+	}
+	if status.State != AllocationStateAllocated {
+		deleteAllocation(resource, created.GetName())
+	}
+	return status, nil
+}
+
+// deleteAllocation is used to remove an allocation that is of no use anymore, failing to do so is only logged.
+// A new context is used, as the context of the request may already be done.
+func deleteAllocation(resource dynamic.ResourceInterface, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), allocationTimeout)
+	defer cancel()
+	if err := resource.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		log.Printf("Error deleting allocation %s: %v", name, err)
+	}
+}
+
+// allocationToUnstructured is used to make a ServerAllocation object into a unstructured object which can interact with dynamic client
+func allocationToUnstructured(allocation *ServerAllocation) (*unstructured.Unstructured, error) {
+	allocation.ApiVersion = crdGroup + "/" + crdVersion
+	allocation.Kind = allocationKind
+	bodyBytes, err := json.Marshal(allocation)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(bodyBytes); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
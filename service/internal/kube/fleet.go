@@ -35,9 +35,10 @@ const (
 )
 
 type FleetScaling struct {
-	Replicas          int32    `json:"replicas"`
-	PrioritizeAllowed bool     `json:"prioritizeAllowed"`
-	AgePriority       Priority `json:"agePriority"`
+	Replicas            int32    `json:"replicas"`
+	PrioritizeAllowed   bool     `json:"prioritizeAllowed"`
+	AgePriority         Priority `json:"agePriority"`
+	MaxConcurrentDrains *int32   `json:"maxConcurrentDrains,omitempty"`
 }
 
 type Fleet struct {
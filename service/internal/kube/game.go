@@ -6,8 +6,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"strconv"
 )
 
 var GameGCR = schema.GroupVersionResource{
@@ -17,7 +19,41 @@ var GameGCR = schema.GroupVersionResource{
 }
 
 type GameTypeSpec struct {
-	FleetSpec FleetSpec `json:"fleetSpec"`
+	FleetSpec FleetSpec       `json:"fleetSpec"`
+	Strategy  *UpdateStrategy `json:"strategy,omitempty"`
+	// How many revisions of the server spec the operator keeps to roll back to
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+type UpdateStrategyType string
+
+const (
+	RollingUpdateStrategy UpdateStrategyType = "RollingUpdate"
+	RecreateStrategy      UpdateStrategyType = "Recreate"
+	CanaryStrategy        UpdateStrategyType = "Canary"
+)
+
+const (
+	canaryAnnotation = "gameserver.falloria.com/canary"
+	CanaryPromote    = "promote"
+	CanaryAbort      = "abort"
+
+	rollbackAnnotation = "gameserver.falloria.com/rollback-to"
+)
+
+type UpdateStrategy struct {
+	Type          UpdateStrategyType `json:"type,omitempty"`
+	RollingUpdate *RollingUpdate     `json:"rollingUpdate,omitempty"`
+	Canary        *CanaryUpdate      `json:"canary,omitempty"`
+}
+
+type CanaryUpdate struct {
+	Replicas *intstr.IntOrString `json:"replicas,omitempty"`
+}
+
+type RollingUpdate struct {
+	MaxSurge       *intstr.IntOrString `json:"maxSurge,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 type GameType struct {
@@ -59,6 +95,35 @@ func DeleteGame(ctx context.Context, metadata Metadata, client *dynamic.DynamicC
 	return nil
 }
 
+// SetCanaryAction marks the running canary of the game to be promoted or aborted, the operator acts on it on the next reconcile.
+func SetCanaryAction(ctx context.Context, metadata Metadata, action string, client *dynamic.DynamicClient) error {
+	return setGameAnnotation(ctx, metadata, canaryAnnotation, action, client)
+}
+
+// RollbackGame marks the game to be rolled back to one of the revisions in its status, the operator acts on it on the next reconcile.
+func RollbackGame(ctx context.Context, metadata Metadata, revision int64, client *dynamic.DynamicClient) error {
+	return setGameAnnotation(ctx, metadata, rollbackAnnotation, strconv.FormatInt(revision, 10), client)
+}
+
+// setGameAnnotation sets a single annotation on the game, keeping the other ones
+func setGameAnnotation(ctx context.Context, metadata Metadata, key string, value string, client *dynamic.DynamicClient) error {
+	resource := client.Resource(GameGCR).Namespace(metadata.Namespace)
+	game, err := resource.Get(ctx, metadata.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	annotations := game.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	game.SetAnnotations(annotations)
+
+	_, err = resource.Update(ctx, game, metav1.UpdateOptions{})
+	return err
+}
+
 // removeFleetsForGame is used for deleting all fleets related to a game. This is used when force is true.
 func removeFleetsForGame(ctx context.Context, metadata Metadata, client *dynamic.DynamicClient, clientset *kubernetes.Clientset, force bool) error {
 	fleets, err := client.Resource(FleetGCR).Namespace(metadata.Namespace).List(ctx, metav1.ListOptions{})
@@ -7,4 +7,7 @@ const (
 	gameResourceName   = "gametypes"
 	fleetResourceName  = "fleets"
 	serverResourceName = "servers"
+
+	allocationResourceName = "serverallocations"
+	allocationKind         = "ServerAllocation"
 )
@@ -3,8 +3,11 @@ package kube
 import (
 	"context"
 	"encoding/json"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -12,36 +15,115 @@ import (
 
 type PolicyStrategy string
 type SyncStrategy string
+type TargetKind string
 
 var validPolicyStrategies = map[PolicyStrategy]struct{}{
-	Webhook: {},
+	Webhook:    {},
+	Buffer:     {},
+	Schedule:   {},
+	Prometheus: {},
+	Grpc:       {},
 	// Add new strategies here as needed
 }
 var validSyncStrategy = map[SyncStrategy]struct{}{
 	FixedInterval: {},
+	OnChange:      {},
 	// Add new strategies here as needed
 }
 
 var (
-	Webhook       PolicyStrategy = "webhook"
-	FixedInterval SyncStrategy   = "fixedinterval"
+	Webhook        PolicyStrategy = "webhook"
+	Buffer         PolicyStrategy = "buffer"
+	Schedule       PolicyStrategy = "schedule"
+	Prometheus     PolicyStrategy = "prometheus"
+	Grpc           PolicyStrategy = "grpc"
+	FixedInterval  SyncStrategy   = "fixedinterval"
+	OnChange       SyncStrategy   = "onchange"
+	GameTypeTarget TargetKind     = "GameType"
+	FleetTarget    TargetKind     = "Fleet"
 )
 
 type GameAutoscalerSpec struct {
-	GameTypeName    string          `json:"gameTypeName"`
-	AutoscalePolicy AutoscalePolicy `json:"policy"`
-	Sync            Sync            `json:"sync"`
+	GameTypeName     string              `json:"gameTypeName,omitempty"`
+	TargetRef        *TargetRef          `json:"targetRef,omitempty"`
+	AutoscalePolicy  AutoscalePolicy     `json:"policy"`
+	Sync             *Sync               `json:"sync,omitempty"`
+	MinReplicas      *int32              `json:"minReplicas,omitempty"`
+	MaxReplicas      *int32              `json:"maxReplicas,omitempty"`
+	MaxScaleUpStep   *intstr.IntOrString `json:"maxScaleUpStep,omitempty"`
+	MaxScaleDownStep *intstr.IntOrString `json:"maxScaleDownStep,omitempty"`
+	Behavior         *AutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+type AutoscalerBehavior struct {
+	ScaleUp   *ScalingRules `json:"scaleUp,omitempty"`
+	ScaleDown *ScalingRules `json:"scaleDown,omitempty"`
+}
+
+type ScalingRules struct {
+	StabilizationWindow *metav1.Duration `json:"stabilizationWindow,omitempty"`
+	Cooldown            *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+type TargetRef struct {
+	Kind TargetKind `json:"kind"`
+	Name string     `json:"name"`
 }
 
 type AutoscalePolicy struct {
-	Type                  PolicyStrategy        `json:"type"`
-	WebhookAutoscalerSpec WebhookAutoscalerSpec `json:"webhook"`
+	Type                     PolicyStrategy            `json:"type"`
+	WebhookAutoscalerSpec    *WebhookAutoscalerSpec    `json:"webhook,omitempty"`
+	BufferAutoscalerSpec     *BufferAutoscalerSpec     `json:"buffer,omitempty"`
+	ScheduleAutoscalerSpec   *ScheduleAutoscalerSpec   `json:"schedule,omitempty"`
+	PrometheusAutoscalerSpec *PrometheusAutoscalerSpec `json:"prometheus,omitempty"`
+	GrpcAutoscalerSpec       *GrpcAutoscalerSpec       `json:"grpc,omitempty"`
+}
+
+type GrpcAutoscalerSpec struct {
+	Address             string                       `json:"address"`
+	CABundle            []byte                       `json:"caBundle,omitempty"`
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+}
+
+type PrometheusAutoscalerSpec struct {
+	ServerUrl             string            `json:"serverUrl"`
+	Query                 string            `json:"query"`
+	TargetValuePerReplica resource.Quantity `json:"targetValuePerReplica"`
+}
+
+type ScheduleAutoscalerSpec struct {
+	Entries         []ScheduleEntry `json:"entries"`
+	DefaultReplicas *int32          `json:"defaultReplicas,omitempty"`
+}
+
+type ScheduleEntry struct {
+	Name     string           `json:"name,omitempty"`
+	Cron     string           `json:"cron"`
+	TimeZone *string          `json:"timeZone,omitempty"`
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	Replicas int32            `json:"replicas"`
+}
+
+type BufferAutoscalerSpec struct {
+	BufferSize  intstr.IntOrString `json:"bufferSize"`
+	MinReplicas *int32             `json:"minReplicas,omitempty"`
+	MaxReplicas *int32             `json:"maxReplicas,omitempty"`
 }
 
 type WebhookAutoscalerSpec struct {
-	Url     *string `json:"url"`
-	Path    string  `json:"path"`
-	Service Service `json:"service"`
+	Url                  *string                      `json:"url"`
+	Path                 string                       `json:"path"`
+	Service              Service                      `json:"service"`
+	CABundle             []byte                       `json:"caBundle,omitempty"`
+	ClientCertSecretRef  *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+	BearerTokenSecretRef *corev1.SecretKeySelector    `json:"bearerTokenSecretRef,omitempty"`
+	Headers              []WebhookHeader              `json:"headers,omitempty"`
+	HMACSecretRef        *corev1.SecretKeySelector    `json:"hmacSecretRef,omitempty"`
+}
+
+type WebhookHeader struct {
+	Name      string                   `json:"name"`
+	ValueFrom corev1.SecretKeySelector `json:"valueFrom"`
 }
 
 type Service struct {
@@ -50,8 +132,10 @@ type Service struct {
 	Port      int    `json:"port"`
 }
 type Sync struct {
-	Type          SyncStrategy `json:"type"`
-	FixedInterval int          `json:"fixedInterval"`
+	Type        SyncStrategy     `json:"type"`
+	Time        *metav1.Duration `json:"interval,omitempty"`
+	Debounce    *metav1.Duration `json:"debounce,omitempty"`
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
 }
 
 type GameAutoscaler struct {
@@ -28,12 +28,15 @@ type ServerSpec struct {
 	AllowForceDelete bool             `json:"allowForceDelete,omitempty"`
 	SidecarSettings  *SidecarSettings `json:"sidecar,omitempty"`
 	GameInfo         *GameInfo        `json:"gameInfo,omitempty"`
+	RestartPolicy    string           `json:"restartPolicy,omitempty"`
 }
 
 type SidecarSettings struct {
-	Port         *int   `json:"port,omitempty"`
-	SidecarImage string `json:"image,omitempty"`
-	LogDebug     bool   `json:"logDebug,omitempty"`
+	Port              *int             `json:"port,omitempty"`
+	SidecarImage      string           `json:"image,omitempty"`
+	LogDebug          bool             `json:"logDebug,omitempty"`
+	Mode              string           `json:"mode,omitempty"`
+	HeartbeatDeadline *metav1.Duration `json:"heartbeatDeadline,omitempty"`
 }
 
 type Server struct {
@@ -20,10 +20,14 @@ func SetupRoutes(a *app.App) {
 
 	a.Mux.HandleFunc("POST /game", handlers.CreateGame(a))
 	a.Mux.HandleFunc("DELETE /game", handlers.DeleteGame(a))
+	a.Mux.HandleFunc("POST /game/canary", handlers.CanaryGame(a))
+	a.Mux.HandleFunc("POST /game/rollback", handlers.RollbackGame(a))
 
 	a.Mux.HandleFunc("POST /scaler", handlers.CreateScaler(a))
 	a.Mux.HandleFunc("DELETE /scaler", handlers.DeleteScaler(a))
 
+	a.Mux.HandleFunc("POST /allocation", handlers.AllocateServer(a))
+
 	a.Mux.HandleFunc("/health", handlers.Health(a))
 	err := http.ListenAndServe(":8080", a.Mux)
 	if err != nil {
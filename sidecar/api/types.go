@@ -0,0 +1,31 @@
+// Package api holds the bodies the sidecar sends and receives, shared by the sidecar and the sdk.
+package api
+
+import "time"
+
+type DeleteRequest struct {
+	Allowed bool `json:"allowed"`
+}
+
+type ShutdownRequest struct {
+	Shutdown bool `json:"shutdown"`
+}
+
+type PlayersRequest struct {
+	Players  int `json:"players"`
+	Capacity int `json:"capacity"`
+}
+
+type HeartbeatRequest struct {
+	Healthy       bool      `json:"healthy"`
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+	Deadline      string    `json:"deadline,omitempty"`
+}
+
+type WatchRequest struct {
+	Version       uint64 `json:"version"`
+	Shutdown      bool   `json:"shutdown"`
+	DeleteAllowed bool   `json:"deleteAllowed"`
+	Players       int    `json:"players"`
+	Capacity      int    `json:"capacity"`
+}
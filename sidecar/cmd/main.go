@@ -3,13 +3,19 @@ package main
 import (
 	"fmt"
 	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/push"
 	"github.com/MirrorStudios/fallernetes-sidecar/internal/routes"
 	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 )
 
+// pushRetryInterval is how often the state is pushed again in push mode, in case the last push failed.
+// With a heartbeat deadline it is at most half of the deadline, so a missed deadline is pushed in time.
+const pushRetryInterval = 10 * time.Second
+
 func main() {
 	var port int
 	portStr := os.Getenv("PORT")
@@ -23,6 +29,22 @@ func main() {
 		fmt.Printf("Invalid port value: %v\n", err)
 		return
 	}
+	capacity := 0
+	if capacityStr := os.Getenv("SERVER_CAPACITY"); capacityStr != "" {
+		capacity, err = strconv.Atoi(capacityStr)
+		if err != nil {
+			fmt.Printf("Invalid capacity value: %v\n", err)
+			return
+		}
+	}
+	var heartbeatDeadline time.Duration
+	if deadlineStr := os.Getenv("HEARTBEAT_DEADLINE"); deadlineStr != "" {
+		heartbeatDeadline, err = time.ParseDuration(deadlineStr)
+		if err != nil {
+			fmt.Printf("Invalid heartbeat deadline value: %v\n", err)
+			return
+		}
+	}
 	level := slog.LevelInfo
 	if isDebug() {
 		level = slog.LevelDebug
@@ -36,8 +58,31 @@ func main() {
 		Mux:               http.NewServeMux(),
 		ShutdownRequested: false,
 		DeleteAllowed:     false,
+		Capacity:          capacity,
 		Port:              port,
 		Logger:            logger,
+		HeartbeatDeadline: heartbeatDeadline,
+		LastHeartbeat:     time.Now(),
+		StateFile:         os.Getenv("STATE_FILE"),
+	}
+	if err := a.LoadState(); err != nil {
+		logger.Error("Failed to restore the state, starting with a new state", "error", err)
+	}
+	if os.Getenv("SIDECAR_MODE") == "Push" {
+		pusher, err := push.NewInClusterPodPusher(os.Getenv("POD_NAMESPACE"), os.Getenv("POD_NAME"))
+		if err != nil {
+			fmt.Printf("Invalid push mode setup: %v\n", err)
+			return
+		}
+		a.Pusher = pusher
+		if err := a.PushState(); err != nil {
+			logger.Error("Failed to push the state", "error", err)
+		}
+		retryInterval := pushRetryInterval
+		if heartbeatDeadline > 0 {
+			retryInterval = min(retryInterval, heartbeatDeadline/2)
+		}
+		go a.RetryPushes(retryInterval)
 	}
 
 	routes.SetupRoutes(&a)
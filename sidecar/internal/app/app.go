@@ -3,13 +3,72 @@ package app
 import (
 	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // App struct is where most of the state of the sidecar is stored, along with the used http Mux.
+// DeleteAllowed, ShutdownRequested, Players and LastHeartbeat are only the starting values once the sidecar serves requests.
+// The state is guarded by the state lock and has to be read and changed through GetState and UpdateState.
+// The heartbeat is kept outside of the lock, so the liveness probe never waits for the state, and has to be read and
+// changed through the heartbeat methods.
 type App struct {
 	Mux               *http.ServeMux
 	DeleteAllowed     bool
 	ShutdownRequested bool
+	Players           int
+	Capacity          int
 	Port              int
 	Logger            *slog.Logger
+	// HeartbeatDeadline is how long the game may go without a heartbeat, heartbeats are not required when it is zero
+	HeartbeatDeadline time.Duration
+	// LastHeartbeat is when the game sent its last heartbeat, or when the sidecar started
+	LastHeartbeat time.Time
+	// StateFile is where the State is persisted, the state is only kept in memory when it is empty
+	StateFile string
+	// Pusher publishes the State in push mode, the operator polls the sidecar when it is nil
+	Pusher StatePusher
+
+	// stateMutex guards the state, the version that is watched, and the counter of the snapshots that are pushed
+	stateMutex sync.Mutex
+	snapshots  uint64
+	version    uint64
+	watched    *State
+	changed    chan struct{}
+
+	// pushMutex orders the pushes, and guards what was pushed last
+	pushMutex sync.Mutex
+	pushed    *PushedState
+	attempted uint64
+
+	// lastHeartbeat is the last heartbeat in unix nanoseconds, it is zero until the game sends its first heartbeat
+	lastHeartbeat atomic.Int64
+}
+
+// RecordHeartbeat sets the last heartbeat of the game to now, and returns it.
+// In push mode, a game that missed its deadline before is pushed as healthy again.
+func (a *App) RecordHeartbeat() time.Time {
+	previous := a.GetLastHeartbeat()
+	now := time.Now()
+	a.lastHeartbeat.Store(now.UnixNano())
+	if !a.IsHeartbeatHealthy(previous) {
+		if err := a.PushState(); err != nil {
+			a.Logger.Error("Failed to push the state", "error", err)
+		}
+	}
+	return now
+}
+
+// IsHeartbeatHealthy checks the last heartbeat against the deadline, without a deadline the game is always healthy
+func (a *App) IsHeartbeatHealthy(lastHeartbeat time.Time) bool {
+	return a.HeartbeatDeadline <= 0 || time.Since(lastHeartbeat) <= a.HeartbeatDeadline
+}
+
+// GetLastHeartbeat returns when the game sent its last heartbeat
+func (a *App) GetLastHeartbeat() time.Time {
+	if nanos := a.lastHeartbeat.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return a.LastHeartbeat
 }
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"time"
+)
+
+// StatePusher publishes the State of the sidecar, so the operator can read it without asking the sidecar
+type StatePusher interface {
+	PushState(PushedState) error
+}
+
+// PushedState is what the sidecar pushes, the State and whether the game sent a heartbeat within its deadline.
+// The heartbeat is not part of the State, as it starts over when the sidecar restarts.
+type PushedState struct {
+	State
+	HeartbeatHealthy bool
+}
+
+// PushState pushes the state when the sidecar is in push mode, if it changed since the last successful push.
+// The state is copied under the state lock and pushed after releasing it, so a slow api server does not block the sidecar.
+// Every copy is numbered, and a copy older than the last one handed to the pusher is skipped,
+// so a push that waited for another one never reverts the pod to an older state.
+func (a *App) PushState() error {
+	if a.Pusher == nil {
+		return nil
+	}
+	return a.pushSnapshot(a.snapshotState())
+}
+
+// snapshotState copies the state to push under the state lock, and numbers the copy
+func (a *App) snapshotState() (uint64, PushedState) {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	a.snapshots++
+	return a.snapshots, PushedState{State: a.currentState(), HeartbeatHealthy: a.IsHeartbeatHealthy(a.GetLastHeartbeat())}
+}
+
+// pushSnapshot pushes a copy of the state, unless a newer copy was already handed to the pusher
+func (a *App) pushSnapshot(snapshot uint64, state PushedState) error {
+	a.pushMutex.Lock()
+	defer a.pushMutex.Unlock()
+	if snapshot < a.attempted {
+		return nil
+	}
+	a.attempted = snapshot
+	if a.pushed != nil && *a.pushed == state {
+		return nil
+	}
+	if err := a.Pusher.PushState(state); err != nil {
+		return err
+	}
+	a.pushed = &state
+	return nil
+}
+
+// RetryPushes pushes the state on every interval, so a failed push is not lost when the state does not change again.
+// A missed heartbeat deadline is only noticed here, so the interval should be shorter than the deadline.
+func (a *App) RetryPushes(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := a.PushState(); err != nil {
+			a.Logger.Error("Failed to push the state", "error", err)
+		}
+	}
+}
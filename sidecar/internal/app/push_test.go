@@ -0,0 +1,99 @@
+package app
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type testPusher struct {
+	pushes []PushedState
+	fail   bool
+}
+
+func (p *testPusher) PushState(state PushedState) error {
+	if p.fail {
+		return errors.New("push failed")
+	}
+	p.pushes = append(p.pushes, state)
+	return nil
+}
+
+func TestUpdateStatePushes(t *testing.T) {
+	pusher := &testPusher{}
+	a := &App{Pusher: pusher, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	a.UpdateState(func(state *State) { state.DeleteAllowed = true })
+	a.UpdateState(func(state *State) {})
+	if len(pusher.pushes) != 1 || !pusher.pushes[0].DeleteAllowed {
+		t.Fatalf("expected one push with delete allowed, got %v", pusher.pushes)
+	}
+
+	a.UpdateState(func(state *State) { state.Players = 2 })
+	if len(pusher.pushes) != 2 || pusher.pushes[1].Players != 2 {
+		t.Fatalf("expected a second push with 2 players, got %v", pusher.pushes)
+	}
+}
+
+func TestPushStateRetriesFailedPush(t *testing.T) {
+	pusher := &testPusher{fail: true}
+	a := &App{Pusher: pusher, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	a.UpdateState(func(state *State) { state.ShutdownRequested = true })
+	if len(pusher.pushes) != 0 {
+		t.Fatalf("expected the push to fail, got %v", pusher.pushes)
+	}
+	if err := a.PushState(); err == nil {
+		t.Fatalf("expected the push to fail")
+	}
+	pusher.fail = false
+	if err := a.PushState(); err != nil {
+		t.Fatalf("Error pushing state: %v", err)
+	}
+	if len(pusher.pushes) != 1 || !pusher.pushes[0].ShutdownRequested {
+		t.Fatalf("expected the failed push to be retried, got %v", pusher.pushes)
+	}
+}
+
+func TestPushStateHeartbeat(t *testing.T) {
+	pusher := &testPusher{}
+	a := &App{
+		Pusher:            pusher,
+		HeartbeatDeadline: time.Minute,
+		LastHeartbeat:     time.Now().Add(-2 * time.Minute),
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	if err := a.PushState(); err != nil {
+		t.Fatalf("Error pushing state: %v", err)
+	}
+	if len(pusher.pushes) != 1 || pusher.pushes[0].HeartbeatHealthy {
+		t.Fatalf("expected a push of the missed deadline, got %v", pusher.pushes)
+	}
+	a.RecordHeartbeat()
+	a.RecordHeartbeat()
+	if len(pusher.pushes) != 2 || !pusher.pushes[1].HeartbeatHealthy {
+		t.Fatalf("expected one push once the game is healthy again, got %v", pusher.pushes)
+	}
+}
+
+func TestPushStateSkipsOlderStates(t *testing.T) {
+	pusher := &testPusher{}
+	a := &App{Pusher: pusher, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	a.updateState(func(state *State) { state.Players = 1 })
+	older, olderState := a.snapshotState()
+	a.updateState(func(state *State) { state.Players = 2 })
+	newer, newerState := a.snapshotState()
+
+	// The older copy only gets to push after the newer one, like when it waited for a slow push
+	if err := a.pushSnapshot(newer, newerState); err != nil {
+		t.Fatalf("Error pushing state: %v", err)
+	}
+	if err := a.pushSnapshot(older, olderState); err != nil {
+		t.Fatalf("Error pushing state: %v", err)
+	}
+	if len(pusher.pushes) != 1 || pusher.pushes[0].Players != 2 {
+		t.Fatalf("expected only the newer state to be pushed, got %v", pusher.pushes)
+	}
+}
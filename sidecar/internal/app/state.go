@@ -0,0 +1,120 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// State is the part of the App that is persisted, so it survives restarts of the sidecar container
+type State struct {
+	DeleteAllowed     bool `json:"deleteAllowed"`
+	ShutdownRequested bool `json:"shutdownRequested"`
+	Players           int  `json:"players"`
+}
+
+// LoadState restores the state from the state file, nothing is restored when there is no state file yet
+func (a *App) LoadState() error {
+	if a.StateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(a.StateFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode state file: %w", err)
+	}
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	a.DeleteAllowed = state.DeleteAllowed
+	a.ShutdownRequested = state.ShutdownRequested
+	a.Players = state.Players
+	return nil
+}
+
+// SaveState writes the state to the state file.
+// The state is written to a temporary file first, so a restart while writing never leaves a partial state behind.
+func (a *App) SaveState() error {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.saveState()
+}
+
+// saveState writes the state to the state file, the state lock has to be held
+func (a *App) saveState() error {
+	if a.StateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(a.currentState())
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(a.StateFile), filepath.Base(a.StateFile)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), a.StateFile); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+// GetState returns a copy of the current state
+func (a *App) GetState() State {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	return a.currentState()
+}
+
+// UpdateState changes the state with update under the state lock.
+// When the state changed, the watchers are notified and the state is persisted before the lock is released,
+// so changes are never saved out of order. It is pushed after the lock is released. The new state is returned.
+// Failures to persist or push are only logged, as the state is still kept in memory and the push is retried.
+func (a *App) UpdateState(update func(*State)) State {
+	state := a.updateState(update)
+	if err := a.PushState(); err != nil {
+		a.Logger.Error("Failed to push the state", "error", err)
+	}
+	return state
+}
+
+// updateState changes, notifies and persists the state under the state lock
+func (a *App) updateState(update func(*State)) State {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	state := a.currentState()
+	update(&state)
+	a.DeleteAllowed = state.DeleteAllowed
+	a.ShutdownRequested = state.ShutdownRequested
+	a.Players = state.Players
+	a.notifyWatchers()
+	if err := a.saveState(); err != nil {
+		a.Logger.Error("Failed to persist the state", "error", err)
+	}
+	return state
+}
+
+// currentState collects the part of the App that is persisted and pushed, the state lock has to be held
+func (a *App) currentState() State {
+	return State{
+		DeleteAllowed:     a.DeleteAllowed,
+		ShutdownRequested: a.ShutdownRequested,
+		Players:           a.Players,
+	}
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	a := &App{StateFile: stateFile, DeleteAllowed: true, ShutdownRequested: true, Players: 4}
+	if err := a.SaveState(); err != nil {
+		t.Fatalf("Error saving state: %v", err)
+	}
+
+	restarted := &App{StateFile: stateFile}
+	if err := restarted.LoadState(); err != nil {
+		t.Fatalf("Error loading state: %v", err)
+	}
+	if state := restarted.GetState(); !state.DeleteAllowed || !state.ShutdownRequested || state.Players != 4 {
+		t.Fatalf("expected the saved state to be restored, got %+v", state)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(stateFile))
+	if err != nil {
+		t.Fatalf("Error reading state directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the state file to be left, got %d files", len(entries))
+	}
+}
+
+func TestLoadStateWithoutFile(t *testing.T) {
+	a := &App{StateFile: filepath.Join(t.TempDir(), "state.json")}
+	if err := a.LoadState(); err != nil {
+		t.Fatalf("expected no error for a missing state file, got %v", err)
+	}
+	if state := a.GetState(); state.DeleteAllowed || state.ShutdownRequested {
+		t.Fatalf("expected the default state, got %+v", state)
+	}
+
+	disabled := &App{DeleteAllowed: true}
+	if err := disabled.SaveState(); err != nil {
+		t.Fatalf("expected no error without a state file, got %v", err)
+	}
+	if err := disabled.LoadState(); err != nil || !disabled.GetState().DeleteAllowed {
+		t.Fatalf("expected the state to be kept without a state file, got %v", err)
+	}
+}
+
+func TestLoadStateInvalid(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(stateFile, []byte("{invalid_json}"), 0o644); err != nil {
+		t.Fatalf("Error writing state file: %v", err)
+	}
+	a := &App{StateFile: stateFile}
+	if err := a.LoadState(); err == nil {
+		t.Fatalf("expected an error for an invalid state file")
+	}
+}
+
+func TestUpdateStateConcurrently(t *testing.T) {
+	pusher := &testPusher{}
+	a := &App{
+		StateFile: filepath.Join(t.TempDir(), "state.json"),
+		Pusher:    pusher,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	a.Watch()
+
+	const updates = 50
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			a.UpdateState(func(state *State) { state.Players++ })
+		}()
+		go func() {
+			defer wg.Done()
+			a.RecordHeartbeat()
+			if err := a.PushState(); err != nil {
+				t.Errorf("Error pushing state: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			a.Watch()
+			a.GetState()
+			a.GetLastHeartbeat()
+		}()
+	}
+	wg.Wait()
+
+	if state := a.GetState(); state.Players != updates {
+		t.Fatalf("expected %d players, got %+v", updates, state)
+	}
+	if version, state, _ := a.Watch(); version != updates || state.Players != updates {
+		t.Fatalf("expected version %d with %d players, got %d %+v", updates, updates, version, state)
+	}
+	if last := pusher.pushes[len(pusher.pushes)-1]; last.Players != updates {
+		t.Fatalf("expected the last push to have %d players, got %+v", updates, last)
+	}
+	restarted := &App{StateFile: a.StateFile}
+	if err := restarted.LoadState(); err != nil {
+		t.Fatalf("Error loading state: %v", err)
+	}
+	if state := restarted.GetState(); state.Players != updates {
+		t.Fatalf("expected %d players to be persisted, got %+v", updates, state)
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+// Watch returns the version of the state, the state of that version, and a channel that is closed once the state changes.
+// The version only grows while the sidecar runs, it starts over when the sidecar restarts.
+func (a *App) Watch() (uint64, State, <-chan struct{}) {
+	a.stateMutex.Lock()
+	defer a.stateMutex.Unlock()
+	if a.changed == nil {
+		state := a.currentState()
+		a.watched = &state
+		a.changed = make(chan struct{})
+	}
+	return a.version, *a.watched, a.changed
+}
+
+// notifyWatchers moves the state to a new version and wakes up the watchers, if the state changed since the last version.
+// The state lock has to be held.
+func (a *App) notifyWatchers() {
+	// Nobody watched yet, so the first watcher takes the state as it is then
+	if a.changed == nil {
+		return
+	}
+	state := a.currentState()
+	if *a.watched == state {
+		return
+	}
+	a.watched = &state
+	a.version++
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestWatchOnlyChangesWithTheState(t *testing.T) {
+	a := &App{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	version, state, changed := a.Watch()
+	if version != 0 || state.ShutdownRequested {
+		t.Fatalf("expected the first version without a shutdown, got %d %+v", version, state)
+	}
+
+	a.UpdateState(func(state *State) {})
+	select {
+	case <-changed:
+		t.Fatalf("expected no new version without a change")
+	default:
+	}
+
+	a.UpdateState(func(state *State) { state.ShutdownRequested = true })
+	select {
+	case <-changed:
+	default:
+		t.Fatalf("expected the watchers to be notified")
+	}
+	version, state, _ = a.Watch()
+	if version != 1 || !state.ShutdownRequested {
+		t.Fatalf("expected the second version with a shutdown, got %d %+v", version, state)
+	}
+}
@@ -2,20 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
 	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
 	"log"
 	"net/http"
 )
 
-type DeleteRequest struct {
-	Allowed bool `json:"allowed"`
-}
-
 // IsDeleteAllowed is used by the operator to check if this can be deleted
 func IsDeleteAllowed(a *app.App) func(http.ResponseWriter, *http.Request) {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		err := json.NewEncoder(w).Encode(DeleteRequest{Allowed: a.DeleteAllowed})
+		err := json.NewEncoder(w).Encode(api.DeleteRequest{Allowed: a.GetState().DeleteAllowed})
 		if err != nil {
 			log.Printf("Error encoding response: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
@@ -28,17 +25,19 @@ func IsDeleteAllowed(a *app.App) func(http.ResponseWriter, *http.Request) {
 func SetDeleteAllowed(a *app.App) func(http.ResponseWriter, *http.Request) {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		var request DeleteRequest
+		var request api.DeleteRequest
 		err := json.NewDecoder(r.Body).Decode(&request)
 		if err != nil {
 			log.Printf("Error decoding request: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		if a.DeleteAllowed != request.Allowed {
-			a.Logger.Info("Allowed will be updated", "current allowed", a.DeleteAllowed, "request allowed", request.Allowed)
-		}
-		a.DeleteAllowed = request.Allowed
+		a.UpdateState(func(state *app.State) {
+			if state.DeleteAllowed != request.Allowed {
+				a.Logger.Info("Allowed will be updated", "current allowed", state.DeleteAllowed, "request allowed", request.Allowed)
+			}
+			state.DeleteAllowed = request.Allowed
+		})
 		w.Header().Set("Content-Type", "application/json")
 		err = json.NewEncoder(w).Encode(request)
 		if err != nil {
@@ -3,14 +3,20 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
 	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 )
 
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
 func TestIsDeleteAllowed(t *testing.T) {
-	a := &app.App{DeleteAllowed: true}
+	a := &app.App{DeleteAllowed: true, Logger: testLogger}
 	req := httptest.NewRequest(http.MethodGet, "/allow_delete", nil)
 	rec := httptest.NewRecorder()
 
@@ -23,7 +29,7 @@ func TestIsDeleteAllowed(t *testing.T) {
 		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
 	}
 
-	var response DeleteRequest
+	var response api.DeleteRequest
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		t.Fatalf("Error decoding response: %v", err)
@@ -35,8 +41,8 @@ func TestIsDeleteAllowed(t *testing.T) {
 }
 
 func TestSetDeleteAllowed(t *testing.T) {
-	a := &app.App{DeleteAllowed: false}
-	requestBody, err := json.Marshal(DeleteRequest{Allowed: true})
+	a := &app.App{DeleteAllowed: false, Logger: testLogger}
+	requestBody, err := json.Marshal(api.DeleteRequest{Allowed: true})
 	if err != nil {
 		t.Fatalf("Error encoding request body: %v", err)
 	}
@@ -52,7 +58,7 @@ func TestSetDeleteAllowed(t *testing.T) {
 		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
 	}
 
-	var response DeleteRequest
+	var response api.DeleteRequest
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		t.Fatalf("Error decoding response: %v", err)
@@ -64,7 +70,7 @@ func TestSetDeleteAllowed(t *testing.T) {
 }
 
 func TestSetDeleteAllowedInvalid(t *testing.T) {
-	a := &app.App{DeleteAllowed: false}
+	a := &app.App{DeleteAllowed: false, Logger: testLogger}
 
 	invalidBody := bytes.NewBufferString("{invalid_json}")
 
@@ -80,7 +86,33 @@ func TestSetDeleteAllowedInvalid(t *testing.T) {
 		t.Fatalf("expected status 400 Bad Request Error, got %v", resp.StatusCode)
 	}
 
-	if a.DeleteAllowed != false {
-		t.Errorf("expected DeleteAllowed=false, got %v", a.DeleteAllowed)
+	if a.GetState().DeleteAllowed != false {
+		t.Errorf("expected DeleteAllowed=false, got %v", a.GetState().DeleteAllowed)
+	}
+}
+
+func TestSetDeleteAllowedPersists(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	a := &app.App{DeleteAllowed: false, StateFile: stateFile, Logger: testLogger}
+	requestBody, err := json.Marshal(api.DeleteRequest{Allowed: true})
+	if err != nil {
+		t.Fatalf("Error encoding request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/allow_delete", bytes.NewReader(requestBody))
+	rec := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(SetDeleteAllowed(a))
+	handler.ServeHTTP(rec, req)
+
+	if rec.Result().StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d. Expected 200", rec.Result().StatusCode)
+	}
+
+	restarted := &app.App{StateFile: stateFile, Logger: testLogger}
+	if err := restarted.LoadState(); err != nil {
+		t.Fatalf("Error loading state: %v", err)
+	}
+	if !restarted.GetState().DeleteAllowed {
+		t.Fatalf("expected delete allowed to survive a restart")
 	}
 }
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"log"
+	"net/http"
+	"time"
+)
+
+// GetHeartbeat is used by the operator to check if the game sent a heartbeat within its deadline
+// A missed deadline is answered with 503, so the liveness probe of the game containers fails and the kubelet restarts them
+func GetHeartbeat(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := getHeartbeatResponse(a, a.GetLastHeartbeat())
+		w.Header().Set("Content-Type", "application/json")
+		if !response.Healthy {
+			a.Logger.Debug("Heartbeat deadline missed", "last heartbeat", response.LastHeartbeat, "deadline", a.HeartbeatDeadline)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		err := json.NewEncoder(w).Encode(response)
+		if err != nil {
+			log.Printf("Error encoding response: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	})
+}
+
+// SendHeartbeat is used by the game to prove it is still alive, it has to be called within the deadline
+func SendHeartbeat(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastHeartbeat := a.RecordHeartbeat()
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(getHeartbeatResponse(a, lastHeartbeat))
+		if err != nil {
+			log.Printf("Error encoding response: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+// getHeartbeatResponse checks the last heartbeat against the deadline, without a deadline the game is always healthy
+func getHeartbeatResponse(a *app.App, lastHeartbeat time.Time) api.HeartbeatRequest {
+	if a.HeartbeatDeadline <= 0 {
+		return api.HeartbeatRequest{Healthy: true, LastHeartbeat: lastHeartbeat}
+	}
+	return api.HeartbeatRequest{
+		Healthy:       a.IsHeartbeatHealthy(lastHeartbeat),
+		LastHeartbeat: lastHeartbeat,
+		Deadline:      a.HeartbeatDeadline.String(),
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getHeartbeat(t *testing.T, a *app.App, status int) api.HeartbeatRequest {
+	req := httptest.NewRequest(http.MethodGet, "/heartbeat", nil)
+	rec := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(GetHeartbeat(a))
+	handler.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+
+	if resp.StatusCode != status {
+		t.Fatalf("unexpected status code: %d. Expected %d", resp.StatusCode, status)
+	}
+
+	var response api.HeartbeatRequest
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+	return response
+}
+
+func TestGetHeartbeatWithoutDeadline(t *testing.T) {
+	a := &app.App{LastHeartbeat: time.Now().Add(-time.Hour), Logger: testLogger}
+
+	if response := getHeartbeat(t, a, http.StatusOK); !response.Healthy {
+		t.Fatalf("expected a healthy game without a deadline")
+	}
+}
+
+func TestGetHeartbeatMissedDeadline(t *testing.T) {
+	a := &app.App{HeartbeatDeadline: time.Minute, LastHeartbeat: time.Now().Add(-2 * time.Minute), Logger: testLogger}
+
+	response := getHeartbeat(t, a, http.StatusServiceUnavailable)
+	if response.Healthy {
+		t.Fatalf("expected an unhealthy game after missing the deadline")
+	}
+	if response.Deadline != "1m0s" {
+		t.Fatalf("expected deadline 1m0s, got %s", response.Deadline)
+	}
+}
+
+func TestSendHeartbeat(t *testing.T) {
+	a := &app.App{HeartbeatDeadline: time.Minute, LastHeartbeat: time.Now().Add(-2 * time.Minute), Logger: testLogger}
+	req := httptest.NewRequest(http.MethodPost, "/heartbeat", nil)
+	rec := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(SendHeartbeat(a))
+	handler.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
+	}
+
+	if time.Since(a.GetLastHeartbeat()) > time.Second {
+		t.Fatalf("expected the last heartbeat to be updated, got %v", a.GetLastHeartbeat())
+	}
+	if response := getHeartbeat(t, a, http.StatusOK); !response.Healthy {
+		t.Fatalf("expected a healthy game after the heartbeat")
+	}
+}
+
+// blockingPusher stands in for an api server that does not answer, until the push is released
+type blockingPusher struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingPusher) PushState(app.PushedState) error {
+	p.started <- struct{}{}
+	<-p.release
+	return nil
+}
+
+func TestHeartbeatWhilePushBlocks(t *testing.T) {
+	pusher := &blockingPusher{started: make(chan struct{}, 1), release: make(chan struct{})}
+	a := &app.App{HeartbeatDeadline: time.Minute, LastHeartbeat: time.Now(), Pusher: pusher, Logger: testLogger}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /heartbeat", GetHeartbeat(a))
+	mux.HandleFunc("POST /heartbeat", SendHeartbeat(a))
+	mux.HandleFunc("GET /players", GetPlayers(a))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	updated := make(chan struct{})
+	go func() {
+		a.UpdateState(func(state *app.State) { state.Players = 1 })
+		close(updated)
+	}()
+	<-pusher.started
+	defer func() {
+		close(pusher.release)
+		<-updated
+	}()
+
+	// The liveness probe of the game gives up after a second
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(server.URL + "/heartbeat")
+	if err != nil {
+		t.Fatalf("expected the heartbeat to answer while the push blocks: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
+	}
+	resp, err = client.Post(server.URL+"/heartbeat", "application/json", nil)
+	if err != nil {
+		t.Fatalf("expected the game to send heartbeats while the push blocks: %v", err)
+	}
+	resp.Body.Close()
+	resp, err = client.Get(server.URL + "/players")
+	if err != nil {
+		t.Fatalf("expected the state to be readable while the push blocks: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"log"
+	"net/http"
+)
+
+// GetPlayers is used by the operator to read how many players the server currently has
+func GetPlayers(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(api.PlayersRequest{Players: a.GetState().Players, Capacity: a.Capacity})
+		if err != nil {
+			log.Printf("Error encoding response: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	})
+}
+
+// SetPlayers is used by the server to report its current amount of players
+func SetPlayers(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		var request api.PlayersRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			log.Printf("Error decoding request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if request.Players < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		state := a.UpdateState(func(state *app.State) {
+			if state.Players != request.Players {
+				a.Logger.Debug("Players will be updated", "current players", state.Players, "request players", request.Players)
+			}
+			state.Players = request.Players
+		})
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(api.PlayersRequest{Players: state.Players, Capacity: a.Capacity})
+		if err != nil {
+			log.Printf("Error encoding response: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPlayers(t *testing.T) {
+	a := &app.App{Players: 3, Capacity: 10, Logger: testLogger}
+	req := httptest.NewRequest(http.MethodGet, "/players", nil)
+	rec := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(GetPlayers(a))
+	handler.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
+	}
+
+	var response api.PlayersRequest
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+
+	if response.Players != 3 || response.Capacity != 10 {
+		t.Fatalf("expected 3 players with capacity 10, got %d players with capacity %d", response.Players, response.Capacity)
+	}
+}
+
+func TestSetPlayers(t *testing.T) {
+	a := &app.App{Capacity: 10, Logger: testLogger}
+	requestBody, err := json.Marshal(api.PlayersRequest{Players: 5})
+	if err != nil {
+		t.Fatalf("Error encoding request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/players", bytes.NewReader(requestBody))
+	rec := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(SetPlayers(a))
+	handler.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
+	}
+
+	var response api.PlayersRequest
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+
+	if a.GetState().Players != 5 || response.Players != 5 {
+		t.Fatalf("expected 5 players, got %d in app and %d in response", a.GetState().Players, response.Players)
+	}
+	if response.Capacity != 10 {
+		t.Fatalf("capacity should not change, got %d", response.Capacity)
+	}
+}
+
+func TestSetPlayersInvalid(t *testing.T) {
+	a := &app.App{Players: 2, Logger: testLogger}
+
+	for _, body := range []string{"{invalid_json}", `{"players": -1}`} {
+		req := httptest.NewRequest(http.MethodPost, "/players", bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+
+		handler := http.HandlerFunc(SetPlayers(a))
+		handler.ServeHTTP(rec, req)
+
+		resp := rec.Result()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected status 400 Bad Request Error for %s, got %v", body, resp.StatusCode)
+		}
+
+		if a.GetState().Players != 2 {
+			t.Errorf("expected Players=2, got %v", a.GetState().Players)
+		}
+	}
+}
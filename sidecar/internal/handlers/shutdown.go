@@ -2,20 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
 	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
 	"log"
 	"net/http"
 )
 
-type ShutdownRequest struct {
-	Shutdown bool `json:"shutdown"`
-}
-
 // IsShutdownRequested is used by the gameserver to check for shutdown requests
 func IsShutdownRequested(a *app.App) func(http.ResponseWriter, *http.Request) {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		err := json.NewEncoder(w).Encode(ShutdownRequest{Shutdown: a.ShutdownRequested})
+		err := json.NewEncoder(w).Encode(api.ShutdownRequest{Shutdown: a.GetState().ShutdownRequested})
 		if err != nil {
 			log.Printf("Error encoding response: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
@@ -28,17 +25,19 @@ func IsShutdownRequested(a *app.App) func(http.ResponseWriter, *http.Request) {
 func SetShutdownRequested(a *app.App) func(http.ResponseWriter, *http.Request) {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		var request ShutdownRequest
+		var request api.ShutdownRequest
 		err := json.NewDecoder(r.Body).Decode(&request)
 		if err != nil {
 			log.Printf("Error decoding request: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		if a.ShutdownRequested != request.Shutdown {
-			a.Logger.Info("Shutdown will be updated", "shutdown allowed", a.ShutdownRequested, "request allowed", request.Shutdown)
-		}
-		a.ShutdownRequested = request.Shutdown
+		a.UpdateState(func(state *app.State) {
+			if state.ShutdownRequested != request.Shutdown {
+				a.Logger.Info("Shutdown will be updated", "shutdown allowed", state.ShutdownRequested, "request allowed", request.Shutdown)
+			}
+			state.ShutdownRequested = request.Shutdown
+		})
 		w.Header().Set("Content-Type", "application/json")
 		err = json.NewEncoder(w).Encode(request)
 		if err != nil {
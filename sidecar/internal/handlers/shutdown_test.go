@@ -3,6 +3,7 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
 	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
 	"net/http"
 	"net/http/httptest"
@@ -10,7 +11,7 @@ import (
 )
 
 func TestIsShutdownRequested(t *testing.T) {
-	a := &app.App{ShutdownRequested: true}
+	a := &app.App{ShutdownRequested: true, Logger: testLogger}
 	req := httptest.NewRequest(http.MethodGet, "/shutdown", nil)
 	rec := httptest.NewRecorder()
 
@@ -23,7 +24,7 @@ func TestIsShutdownRequested(t *testing.T) {
 		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
 	}
 
-	var response ShutdownRequest
+	var response api.ShutdownRequest
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		t.Fatalf("Error decoding response: %v", err)
@@ -35,8 +36,8 @@ func TestIsShutdownRequested(t *testing.T) {
 }
 
 func TestSetShutdownRequested(t *testing.T) {
-	a := &app.App{ShutdownRequested: false}
-	requestBody, err := json.Marshal(ShutdownRequest{Shutdown: true})
+	a := &app.App{ShutdownRequested: false, Logger: testLogger}
+	requestBody, err := json.Marshal(api.ShutdownRequest{Shutdown: true})
 	if err != nil {
 		t.Fatalf("Error encoding request body: %v", err)
 	}
@@ -52,7 +53,7 @@ func TestSetShutdownRequested(t *testing.T) {
 		t.Fatalf("unexpected status code: %d. Expected 200", resp.StatusCode)
 	}
 
-	var response ShutdownRequest
+	var response api.ShutdownRequest
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	if err != nil {
 		t.Fatalf("Error decoding response: %v", err)
@@ -64,7 +65,7 @@ func TestSetShutdownRequested(t *testing.T) {
 }
 
 func TestSetShutdownRequestedInvalid(t *testing.T) {
-	a := &app.App{ShutdownRequested: false}
+	a := &app.App{ShutdownRequested: false, Logger: testLogger}
 
 	invalidBody := bytes.NewBufferString("{invalid_json}")
 
@@ -80,7 +81,7 @@ func TestSetShutdownRequestedInvalid(t *testing.T) {
 		t.Fatalf("expected status 400 Bad Request Error, got %v", resp.StatusCode)
 	}
 
-	if a.ShutdownRequested != false {
-		t.Errorf("expected ShutdownAllowed=false, got %v", a.ShutdownRequested)
+	if a.GetState().ShutdownRequested != false {
+		t.Errorf("expected ShutdownAllowed=false, got %v", a.GetState().ShutdownRequested)
 	}
 }
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DEFAULT_WATCH_TIMEOUT is how long a long-poll waits for a change, when the request does not set a timeout
+const DEFAULT_WATCH_TIMEOUT = 30 * time.Second
+
+// MAX_WATCH_TIMEOUT limits the timeout a long-poll can ask for
+const MAX_WATCH_TIMEOUT = 5 * time.Minute
+
+// KEEPALIVE_INTERVAL is how often a comment is sent over an idle event stream, so proxies do not close it
+const KEEPALIVE_INTERVAL = 15 * time.Second
+
+// WatchShutdown is used by the gameserver to learn about shutdown requests and other state changes as soon as they happen.
+// Clients accepting text/event-stream get server-sent events, other clients long-poll with the version they know.
+func WatchShutdown(a *app.App) func(http.ResponseWriter, *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			streamState(a, w, r)
+			return
+		}
+		longPollState(a, w, r)
+	})
+}
+
+// longPollState answers right away if the version of the request is outdated or missing,
+// and otherwise waits until the state changes or the timeout passes
+func longPollState(a *app.App, w http.ResponseWriter, r *http.Request) {
+	version, state, changed := a.Watch()
+	if versionStr := r.URL.Query().Get("version"); versionStr != "" {
+		known, err := strconv.ParseUint(versionStr, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid version", http.StatusBadRequest)
+			return
+		}
+		timeout := DEFAULT_WATCH_TIMEOUT
+		if timeoutStr := r.URL.Query().Get("timeout"); timeoutStr != "" {
+			timeout, err = time.ParseDuration(timeoutStr)
+			if err != nil || timeout < 0 {
+				http.Error(w, "invalid timeout", http.StatusBadRequest)
+				return
+			}
+			timeout = min(timeout, MAX_WATCH_TIMEOUT)
+		}
+		if known == version {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			select {
+			case <-changed:
+				version, state, _ = a.Watch()
+			case <-timer.C:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(getWatchResponse(a, version, state))
+	if err != nil {
+		log.Printf("Error encoding response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// streamState sends the current state as an event, and another event for every change until the client disconnects
+func streamState(a *app.App, w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	keepalive := time.NewTicker(KEEPALIVE_INTERVAL)
+	defer keepalive.Stop()
+
+	for {
+		version, state, changed := a.Watch()
+		data, err := json.Marshal(getWatchResponse(a, version, state))
+		if err != nil {
+			log.Printf("Error encoding event: %v", err)
+			return
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: state\ndata: %s\n\n", version, data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+	waiting:
+		for {
+			select {
+			case <-changed:
+				break waiting
+			case <-keepalive.C:
+				if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+func getWatchResponse(a *app.App, version uint64, state app.State) api.WatchRequest {
+	return api.WatchRequest{
+		Version:       version,
+		Shutdown:      state.ShutdownRequested,
+		DeleteAllowed: state.DeleteAllowed,
+		Players:       state.Players,
+		Capacity:      a.Capacity,
+	}
+}
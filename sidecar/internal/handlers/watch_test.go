@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newWatchServer(t *testing.T) *httptest.Server {
+	a := &app.App{Capacity: 10, Logger: testLogger}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /shutdown", SetShutdownRequested(a))
+	mux.HandleFunc("GET /shutdown/watch", WatchShutdown(a))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func requestShutdown(t *testing.T, server *httptest.Server) {
+	resp, err := http.Post(server.URL+"/shutdown", "application/json", strings.NewReader(`{"shutdown": true}`))
+	if err != nil {
+		t.Errorf("Error requesting shutdown: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+func longPoll(t *testing.T, server *httptest.Server, query string) (int, api.WatchRequest) {
+	resp, err := http.Get(server.URL + "/shutdown/watch" + query)
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+	defer resp.Body.Close()
+	var response api.WatchRequest
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("Error decoding response: %v", err)
+		}
+	}
+	return resp.StatusCode, response
+}
+
+func TestWatchShutdownLongPoll(t *testing.T) {
+	server := newWatchServer(t)
+
+	status, response := longPoll(t, server, "")
+	if status != http.StatusOK || response.Shutdown || response.Capacity != 10 {
+		t.Fatalf("expected the current state right away, got %d %+v", status, response)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		requestShutdown(t, server)
+	}()
+	start := time.Now()
+	status, changed := longPoll(t, server, "?version=0")
+	if status != http.StatusOK || !changed.Shutdown || changed.Version != response.Version+1 {
+		t.Fatalf("expected the shutdown with a new version, got %d %+v", status, changed)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatalf("expected the long poll to wait for the change")
+	}
+
+	// An outdated version is answered right away
+	status, outdated := longPoll(t, server, "?version=0")
+	if status != http.StatusOK || outdated.Version != changed.Version {
+		t.Fatalf("expected the latest version right away, got %d %+v", status, outdated)
+	}
+}
+
+func TestWatchShutdownLongPollTimeout(t *testing.T) {
+	server := newWatchServer(t)
+
+	status, response := longPoll(t, server, "?version=0&timeout=10ms")
+	if status != http.StatusOK || response.Version != 0 || response.Shutdown {
+		t.Fatalf("expected the unchanged state after the timeout, got %d %+v", status, response)
+	}
+
+	for _, query := range []string{"?version=latest", "?version=0&timeout=soon", "?version=0&timeout=-1s"} {
+		if status, _ := longPoll(t, server, query); status != http.StatusBadRequest {
+			t.Fatalf("expected status 400 Bad Request for %s, got %d", query, status)
+		}
+	}
+}
+
+func TestWatchShutdownStream(t *testing.T) {
+	server := newWatchServer(t)
+	request, err := http.NewRequest(http.MethodGet, server.URL+"/shutdown/watch", nil)
+	if err != nil {
+		t.Fatalf("Error creating request: %v", err)
+	}
+	request.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Error watching: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type %s", resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() api.WatchRequest {
+		var event api.WatchRequest
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Error reading event: %v", err)
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				if err := json.Unmarshal([]byte(data), &event); err != nil {
+					t.Fatalf("Error decoding event: %v", err)
+				}
+			}
+			if line == "\n" {
+				return event
+			}
+		}
+	}
+
+	if event := readEvent(); event.Shutdown {
+		t.Fatalf("expected the first event to have no shutdown, got %+v", event)
+	}
+	requestShutdown(t, server)
+	if event := readEvent(); !event.Shutdown || event.Version != 1 {
+		t.Fatalf("expected an event with the shutdown, got %+v", event)
+	}
+}
@@ -0,0 +1,110 @@
+package push
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The annotations are read by the operator, and have to match the ones it expects
+const (
+	DeleteAllowedAnnotation        = "gameserver.falloria.com/delete-allowed"
+	ShutdownAcknowledgedAnnotation = "gameserver.falloria.com/shutdown-acknowledged"
+	PlayersAnnotation              = "gameserver.falloria.com/players"
+	HeartbeatHealthyAnnotation     = "gameserver.falloria.com/heartbeat-healthy"
+)
+
+// serviceAccountPath is where kubernetes mounts the token and the ca of the ServiceAccount of the pod
+const serviceAccountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// PodPusher pushes the state of the sidecar to the annotations of its own pod, using the ServiceAccount of the pod
+type PodPusher struct {
+	Client    *http.Client
+	Host      string
+	TokenFile string
+	Namespace string
+	PodName   string
+}
+
+type podPatch struct {
+	Metadata podPatchMetadata `json:"metadata"`
+}
+
+type podPatchMetadata struct {
+	Annotations map[string]string `json:"annotations"`
+}
+
+// NewInClusterPodPusher creates a PodPusher that talks to the api server of the cluster the sidecar runs in
+func NewInClusterPodPusher(namespace string, podName string) (*PodPusher, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set, the sidecar is not running in a cluster")
+	}
+	if namespace == "" || podName == "" {
+		return nil, errors.New("POD_NAMESPACE and POD_NAME have to be set to push the state")
+	}
+	ca, err := os.ReadFile(serviceAccountPath + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the ca of the service account: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("failed to parse the ca of the service account")
+	}
+	return &PodPusher{
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+		Host:      "https://" + net.JoinHostPort(host, port),
+		TokenFile: serviceAccountPath + "/token",
+		Namespace: namespace,
+		PodName:   podName,
+	}, nil
+}
+
+// PushState patches the annotations of the pod to match the state.
+// The token is read for every push, as kubernetes rotates it.
+func (p *PodPusher) PushState(state app.PushedState) error {
+	token, err := os.ReadFile(p.TokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the service account token: %w", err)
+	}
+	body, err := json.Marshal(podPatch{Metadata: podPatchMetadata{Annotations: map[string]string{
+		DeleteAllowedAnnotation:        strconv.FormatBool(state.DeleteAllowed),
+		ShutdownAcknowledgedAnnotation: strconv.FormatBool(state.ShutdownRequested),
+		PlayersAnnotation:              strconv.Itoa(state.Players),
+		HeartbeatHealthyAnnotation:     strconv.FormatBool(state.HeartbeatHealthy),
+	}}})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/pods/%s", p.Host, p.Namespace, p.PodName)
+	request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	resp, err := p.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("PATCH request returned: " + resp.Status)
+	}
+	return nil
+}
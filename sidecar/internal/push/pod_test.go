@@ -0,0 +1,70 @@
+package push
+
+import (
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPushState(t *testing.T) {
+	var patch podPatch
+	var request *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			t.Errorf("Error decoding patch: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("my-token\n"), 0o600); err != nil {
+		t.Fatalf("Error writing token: %v", err)
+	}
+	pusher := &PodPusher{Client: server.Client(), Host: server.URL, TokenFile: tokenFile, Namespace: "games", PodName: "server-pod"}
+
+	err := pusher.PushState(app.PushedState{State: app.State{DeleteAllowed: true, Players: 3}, HeartbeatHealthy: true})
+	if err != nil {
+		t.Fatalf("Error pushing state: %v", err)
+	}
+	if request.Method != http.MethodPatch || request.URL.Path != "/api/v1/namespaces/games/pods/server-pod" {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+	}
+	if request.Header.Get("Content-Type") != "application/merge-patch+json" {
+		t.Fatalf("unexpected content type %s", request.Header.Get("Content-Type"))
+	}
+	if request.Header.Get("Authorization") != "Bearer my-token" {
+		t.Fatalf("unexpected authorization %s", request.Header.Get("Authorization"))
+	}
+	annotations := patch.Metadata.Annotations
+	if annotations[DeleteAllowedAnnotation] != "true" || annotations[ShutdownAcknowledgedAnnotation] != "false" || annotations[PlayersAnnotation] != "3" ||
+		annotations[HeartbeatHealthyAnnotation] != "true" {
+		t.Fatalf("unexpected annotations %v", annotations)
+	}
+}
+
+func TestPushStateRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("my-token"), 0o600); err != nil {
+		t.Fatalf("Error writing token: %v", err)
+	}
+	pusher := &PodPusher{Client: server.Client(), Host: server.URL, TokenFile: tokenFile, Namespace: "games", PodName: "server-pod"}
+
+	if err := pusher.PushState(app.PushedState{}); err == nil {
+		t.Fatalf("expected an error when the api server rejects the patch")
+	}
+	pusher.TokenFile = filepath.Join(t.TempDir(), "missing")
+	if err := pusher.PushState(app.PushedState{}); err == nil {
+		t.Fatalf("expected an error without a token")
+	}
+}
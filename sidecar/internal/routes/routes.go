@@ -10,12 +10,7 @@ import (
 
 // SetupRoutes sets up the nessecary routes, their handlers and starts serving http.
 func SetupRoutes(a *app.App) {
-
-	a.Mux.HandleFunc("GET /allow_delete", handlers.IsDeleteAllowed(a))
-	a.Mux.HandleFunc("POST /allow_delete", handlers.SetDeleteAllowed(a))
-	a.Mux.HandleFunc("GET /shutdown", handlers.IsShutdownRequested(a))
-	a.Mux.HandleFunc("POST /shutdown", handlers.SetShutdownRequested(a))
-	a.Mux.HandleFunc("/health", handlers.Health(a))
+	RegisterRoutes(a)
 	loggingHandler := app.LogRoute(a, a.Mux)
 	a.Logger.Info("Starting http server", "port", a.Port)
 	err := http.ListenAndServe(":"+strconv.Itoa(a.Port), loggingHandler)
@@ -23,3 +18,17 @@ func SetupRoutes(a *app.App) {
 		log.Fatalf("Error starting server: %v", err)
 	}
 }
+
+// RegisterRoutes adds the routes and their handlers to the mux of the app, without serving it
+func RegisterRoutes(a *app.App) {
+	a.Mux.HandleFunc("GET /allow_delete", handlers.IsDeleteAllowed(a))
+	a.Mux.HandleFunc("POST /allow_delete", handlers.SetDeleteAllowed(a))
+	a.Mux.HandleFunc("GET /shutdown", handlers.IsShutdownRequested(a))
+	a.Mux.HandleFunc("POST /shutdown", handlers.SetShutdownRequested(a))
+	a.Mux.HandleFunc("GET /shutdown/watch", handlers.WatchShutdown(a))
+	a.Mux.HandleFunc("GET /players", handlers.GetPlayers(a))
+	a.Mux.HandleFunc("POST /players", handlers.SetPlayers(a))
+	a.Mux.HandleFunc("GET /heartbeat", handlers.GetHeartbeat(a))
+	a.Mux.HandleFunc("POST /heartbeat", handlers.SendHeartbeat(a))
+	a.Mux.HandleFunc("/health", handlers.Health(a))
+}
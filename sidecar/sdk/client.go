@@ -0,0 +1,203 @@
+// Package sdk is the client game servers use to talk to their fallernetes sidecar.
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DEFAULT_PORT is the port of the sidecar, when the environment does not set one
+const DEFAULT_PORT = 8080
+
+// DEFAULT_WATCH_INTERVAL is how often WatchShutdown asks sidecars without the watch endpoint, when no interval is given
+const DEFAULT_WATCH_INTERVAL = 5 * time.Second
+
+// WATCH_TIMEOUT is how long a single long-poll of WatchShutdown waits for the sidecar
+const WATCH_TIMEOUT = 30 * time.Second
+
+// Client talks to the sidecar running in the same pod as the game server
+type Client struct {
+	address     string
+	httpClient  *http.Client
+	watchClient *http.Client
+}
+
+// Players is the player count the game reported, and the capacity of the server
+type Players struct {
+	Players  int
+	Capacity int
+}
+
+// NewClient creates a client for the sidecar of the pod.
+// The port is read from SIDECAR_PORT, then PORT, and falls back to DEFAULT_PORT.
+func NewClient() (*Client, error) {
+	port := DEFAULT_PORT
+	for _, name := range []string{"SIDECAR_PORT", "PORT"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", name, value, err)
+		}
+		port = parsed
+		break
+	}
+	return NewClientWithAddress(fmt.Sprintf("http://localhost:%d", port)), nil
+}
+
+// NewClientWithAddress creates a client for the sidecar at the address, like http://localhost:8080
+func NewClientWithAddress(address string) *Client {
+	return &Client{
+		address:     address,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		watchClient: &http.Client{Timeout: WATCH_TIMEOUT + 10*time.Second},
+	}
+}
+
+// SetDeleteAllowed tells the sidecar if the operator may delete the server
+func (c *Client) SetDeleteAllowed(ctx context.Context, allowed bool) error {
+	return c.do(ctx, http.MethodPost, "/allow_delete", api.DeleteRequest{Allowed: allowed}, nil)
+}
+
+// IsDeleteAllowed asks the sidecar if the server allowed its deletion
+func (c *Client) IsDeleteAllowed(ctx context.Context) (bool, error) {
+	var response api.DeleteRequest
+	err := c.do(ctx, http.MethodGet, "/allow_delete", nil, &response)
+	return response.Allowed, err
+}
+
+// IsShutdownRequested asks the sidecar if the operator requested the shutdown of the server
+func (c *Client) IsShutdownRequested(ctx context.Context) (bool, error) {
+	var response api.ShutdownRequest
+	err := c.do(ctx, http.MethodGet, "/shutdown", nil, &response)
+	return response.Shutdown, err
+}
+
+// WatchShutdown long-polls the sidecar until a shutdown was requested.
+// The returned channel is closed once it was, and it is never closed if the context ends first.
+// Sidecars without the watch endpoint are asked on every interval instead, which is also how long failed requests wait.
+func (c *Client) WatchShutdown(ctx context.Context, interval time.Duration) <-chan struct{} {
+	if interval <= 0 {
+		interval = DEFAULT_WATCH_INTERVAL
+	}
+	shutdown := make(chan struct{})
+	go func() {
+		var version *uint64
+		for {
+			state, err := c.watchState(ctx, version)
+			if err == nil {
+				if state.Shutdown {
+					close(shutdown)
+					return
+				}
+				version = &state.Version
+				continue
+			}
+			if requested, err := c.IsShutdownRequested(ctx); err == nil && requested {
+				close(shutdown)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return shutdown
+}
+
+// OnShutdown calls the callback once a shutdown was requested, see WatchShutdown
+func (c *Client) OnShutdown(ctx context.Context, interval time.Duration, callback func()) {
+	shutdown := c.WatchShutdown(ctx, interval)
+	go func() {
+		select {
+		case <-shutdown:
+			callback()
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// SetPlayers reports how many players are on the server
+func (c *Client) SetPlayers(ctx context.Context, players int) (Players, error) {
+	var response api.PlayersRequest
+	err := c.do(ctx, http.MethodPost, "/players", api.PlayersRequest{Players: players}, &response)
+	return Players{Players: response.Players, Capacity: response.Capacity}, err
+}
+
+// GetPlayers reads the player count the server reported, and its capacity
+func (c *Client) GetPlayers(ctx context.Context) (Players, error) {
+	var response api.PlayersRequest
+	err := c.do(ctx, http.MethodGet, "/players", nil, &response)
+	return Players{Players: response.Players, Capacity: response.Capacity}, err
+}
+
+// Heartbeat proves that the game is still alive, it has to be called within the heartbeat deadline of the server
+func (c *Client) Heartbeat(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/heartbeat", nil, nil)
+}
+
+// Health checks if the sidecar is reachable
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, nil)
+}
+
+// watchState waits for the state to move past the version, and returns the current state right away without a version
+func (c *Client) watchState(ctx context.Context, version *uint64) (api.WatchRequest, error) {
+	var response api.WatchRequest
+	path := "/shutdown/watch"
+	if version != nil {
+		path = fmt.Sprintf("%s?version=%d&timeout=%s", path, *version, WATCH_TIMEOUT)
+	}
+	err := c.doWith(ctx, c.watchClient, http.MethodGet, path, nil, &response)
+	return response, err
+}
+
+// do sends the request to the sidecar, and decodes the response into the response if it is set
+func (c *Client) do(ctx context.Context, method string, path string, request any, response any) error {
+	return c.doWith(ctx, c.httpClient, method, path, request, response)
+}
+
+// doWith sends the request with the http client, which lets long-polls outlive the timeout of the other requests
+func (c *Client) doWith(ctx context.Context, httpClient *http.Client, method string, path string, request any, response any) error {
+	var body bytes.Buffer
+	if request != nil {
+		if err := json.NewEncoder(&body).Encode(request); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, method, c.address+path, &body)
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := httpClient.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(method + " request returned: " + resp.Status)
+	}
+	if response == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/MirrorStudios/fallernetes-sidecar/api"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/app"
+	"github.com/MirrorStudios/fallernetes-sidecar/internal/routes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSidecar(t *testing.T) (*app.App, *Client) {
+	a := &app.App{
+		Mux:      http.NewServeMux(),
+		Capacity: 10,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	routes.RegisterRoutes(a)
+	server := httptest.NewServer(a.Mux)
+	t.Cleanup(server.Close)
+	return a, NewClientWithAddress(server.URL)
+}
+
+func TestNewClient(t *testing.T) {
+	t.Setenv("SIDECAR_PORT", "")
+	t.Setenv("PORT", "")
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("Error creating client: %v", err)
+	}
+	if client.address != "http://localhost:8080" {
+		t.Fatalf("expected the default port, got %s", client.address)
+	}
+
+	t.Setenv("PORT", "9090")
+	client, _ = NewClient()
+	if client.address != "http://localhost:9090" {
+		t.Fatalf("expected the port of PORT, got %s", client.address)
+	}
+
+	t.Setenv("SIDECAR_PORT", "7070")
+	client, _ = NewClient()
+	if client.address != "http://localhost:7070" {
+		t.Fatalf("expected SIDECAR_PORT to take precedence, got %s", client.address)
+	}
+
+	t.Setenv("SIDECAR_PORT", "sidecar")
+	if _, err := NewClient(); err == nil {
+		t.Fatalf("expected an error for an invalid port")
+	}
+}
+
+func TestSetDeleteAllowed(t *testing.T) {
+	a, client := newTestSidecar(t)
+	ctx := context.Background()
+
+	if err := client.SetDeleteAllowed(ctx, true); err != nil {
+		t.Fatalf("Error setting delete allowed: %v", err)
+	}
+	if !a.GetState().DeleteAllowed {
+		t.Fatalf("expected the sidecar to allow deletion")
+	}
+	allowed, err := client.IsDeleteAllowed(ctx)
+	if err != nil || !allowed {
+		t.Fatalf("expected deletion to be allowed, got %v with error %v", allowed, err)
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	a, client := newTestSidecar(t)
+	ctx := context.Background()
+
+	players, err := client.SetPlayers(ctx, 4)
+	if err != nil {
+		t.Fatalf("Error setting players: %v", err)
+	}
+	if a.GetState().Players != 4 || players.Players != 4 || players.Capacity != 10 {
+		t.Fatalf("expected 4 players with capacity 10, got %+v in response and %d in the sidecar", players, a.GetState().Players)
+	}
+	players, err = client.GetPlayers(ctx)
+	if err != nil || players.Players != 4 {
+		t.Fatalf("expected 4 players, got %+v with error %v", players, err)
+	}
+	if _, err := client.SetPlayers(ctx, -1); err == nil {
+		t.Fatalf("expected an error for negative players")
+	}
+}
+
+func TestWatchShutdown(t *testing.T) {
+	_, client := newTestSidecar(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	shutdown := client.WatchShutdown(ctx, 10*time.Millisecond)
+	called := make(chan struct{})
+	client.OnShutdown(ctx, 10*time.Millisecond, func() { close(called) })
+	select {
+	case <-shutdown:
+		t.Fatalf("expected no shutdown before it was requested")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The operator requests the shutdown through the sidecar
+	resp, err := http.Post(client.address+"/shutdown", "application/json", strings.NewReader(`{"shutdown": true}`))
+	if err != nil {
+		t.Fatalf("Error requesting shutdown: %v", err)
+	}
+	resp.Body.Close()
+	select {
+	case <-shutdown:
+	case <-ctx.Done():
+		t.Fatalf("expected the shutdown to be noticed")
+	}
+	select {
+	case <-called:
+	case <-ctx.Done():
+		t.Fatalf("expected the shutdown callback to be called")
+	}
+}
+
+func TestWatchShutdownWithoutWatchEndpoint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /shutdown", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.ShutdownRequest{Shutdown: true})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	select {
+	case <-NewClientWithAddress(server.URL).WatchShutdown(ctx, 10*time.Millisecond):
+	case <-ctx.Done():
+		t.Fatalf("expected the shutdown to be noticed by polling")
+	}
+}
+
+func TestWatchShutdownStopsWithContext(t *testing.T) {
+	_, client := newTestSidecar(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	shutdown := client.WatchShutdown(ctx, 10*time.Millisecond)
+	cancel()
+	select {
+	case <-shutdown:
+		t.Fatalf("expected the channel to stay open when the context ends")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHealthAndHeartbeat(t *testing.T) {
+	a, client := newTestSidecar(t)
+	ctx := context.Background()
+
+	if err := client.Health(ctx); err != nil {
+		t.Fatalf("Error checking health: %v", err)
+	}
+	if err := client.Heartbeat(ctx); err != nil {
+		t.Fatalf("Error sending heartbeat: %v", err)
+	}
+	if time.Since(a.GetLastHeartbeat()) > time.Second {
+		t.Fatalf("expected the heartbeat to be recorded, got %v", a.GetLastHeartbeat())
+	}
+
+	unreachable := NewClientWithAddress("http://127.0.0.1:1")
+	if err := unreachable.Health(ctx); err == nil {
+		t.Fatalf("expected an error for an unreachable sidecar")
+	}
+}